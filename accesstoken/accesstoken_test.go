@@ -0,0 +1,178 @@
+package accesstoken_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/accesstoken"
+	"github.com/amidgo/jwt/jwttest"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	issuerURL   = "https://as.example.com"
+	resourceURL = "https://rs.example.com"
+)
+
+func Test_Issuer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+
+	issuer := accesstoken.NewIssuer(keys.SigningMethod(), issuerURL, time.Hour,
+		accesstoken.WithClock(jwt.ClockFunc(func() time.Time { return now })),
+	)
+
+	accessToken, err := issuer.Issue(ctx, accesstoken.Grant{
+		Subject:  "user",
+		ClientID: "client",
+		Audience: []string{resourceURL},
+		Scope:    []string{"read", "write"},
+		Claims:   jwt.Payload{"acr": "mfa", "exp": 1},
+	})
+	assert.NilError(t, err)
+
+	token, err := keys.Parser().ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Header, jwt.Header{Type: "at+jwt", Alg: "ES256"})
+
+	jti := token.Payload["jti"]
+	assert.Assert(t, jti != "")
+	assert.DeepEqual(t, token.Payload, jwt.Payload{
+		"iss":       issuerURL,
+		"sub":       "user",
+		"client_id": "client",
+		"aud":       resourceURL,
+		"scope":     "read write",
+		"acr":       "mfa",
+		"iat":       float64(now.Unix()),
+		"exp":       float64(now.Add(time.Hour).Unix()),
+		"jti":       jti,
+	})
+
+	accessToken, err = issuer.Issue(ctx, accesstoken.Grant{Subject: "client", ClientID: "client", Audience: []string{resourceURL, "https://other.example.com"}})
+	assert.NilError(t, err)
+	token, err = keys.Parser().ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token.Payload["aud"], []any{resourceURL, "https://other.example.com"})
+	_, ok := token.Payload["scope"]
+	assert.Assert(t, !ok)
+
+	_, err = issuer.Issue(ctx, accesstoken.Grant{Subject: "user", ClientID: "client"})
+	assert.ErrorIs(t, err, accesstoken.ErrIncompleteGrant)
+}
+
+func Test_Validator(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t, jwttest.WithIssuer(issuerURL), jwttest.WithAudience(resourceURL))
+	issuer := accesstoken.NewIssuer(keys.SigningMethod(), issuerURL, time.Hour)
+	validator := accesstoken.NewValidator(keys.Parser(), issuerURL, resourceURL)
+
+	issue := func(grant accesstoken.Grant) string {
+		accessToken, err := issuer.Issue(ctx, grant)
+		assert.NilError(t, err)
+		return accessToken
+	}
+	grant := accesstoken.Grant{Subject: "user", ClientID: "client", Audience: []string{resourceURL}, Scope: []string{"read"}}
+
+	// token with at+jwt typ issued by other party with the same keys
+	atCreator := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod(), jwt.WithHeaderType("application/AT+JWT"))
+	mediaTypeToken, err := atCreator.CreateToken(jwt.Payload{
+		"iss": issuerURL, "aud": resourceURL, "sub": "user", "client_id": "client", "jti": "id",
+		"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	assert.NilError(t, err)
+	noClientIDToken, err := atCreator.CreateToken(jwt.Payload{
+		"iss": issuerURL, "aud": resourceURL, "sub": "user", "jti": "id",
+		"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name        string
+		token       string
+		validators  []jwt.TokenValidator
+		expectedErr error
+	}{
+		{
+			name:  "valid",
+			token: issue(grant),
+		},
+		{
+			name:       "valid with scope",
+			token:      issue(grant),
+			validators: []jwt.TokenValidator{accesstoken.RequireScopes("read")},
+		},
+		{
+			name:  "media type typ",
+			token: mediaTypeToken,
+		},
+		{
+			name:        "insufficient scope",
+			token:       issue(grant),
+			validators:  []jwt.TokenValidator{accesstoken.RequireScopes("read", "write")},
+			expectedErr: accesstoken.ErrInsufficientScope,
+		},
+		{
+			name:        "id token replayed as access token",
+			token:       keys.Token(jwt.Payload{"sub": "user", "client_id": "client", "nonce": "n"}),
+			expectedErr: accesstoken.ErrInvalidType,
+		},
+		{
+			name:        "other audience",
+			token:       issue(accesstoken.Grant{Subject: "user", ClientID: "client", Audience: []string{"https://other.example.com"}}),
+			expectedErr: accesstoken.ErrInvalidAudience,
+		},
+		{
+			name: "other issuer",
+			token: func() string {
+				accessToken, err := accesstoken.NewIssuer(keys.SigningMethod(), "https://evil.example.com", time.Hour).Issue(ctx, grant)
+				assert.NilError(t, err)
+				return accessToken
+			}(),
+			expectedErr: accesstoken.ErrInvalidIssuer,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				accessToken, err := accesstoken.NewIssuer(keys.SigningMethod(), issuerURL, time.Hour,
+					accesstoken.WithClock(jwt.ClockFunc(func() time.Time { return time.Now().Add(-2 * time.Hour) })),
+				).Issue(ctx, grant)
+				assert.NilError(t, err)
+				return accessToken
+			}(),
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:        "no client_id",
+			token:       noClientIDToken,
+			expectedErr: accesstoken.ErrMissingClaim,
+		},
+		{
+			name:        "wrong sign",
+			token:       keys.WrongSignatureToken(jwt.Payload{"sub": "user"}),
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+	}
+
+	for _, cs := range cases {
+		token, err := validator.Validate(ctx, cs.token, cs.validators...)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr == nil {
+			assert.Equal(t, token.Payload["sub"], "user", cs.name)
+		}
+	}
+}
+
+func Test_Scopes(t *testing.T) {
+	token := jwt.Token{Payload: jwt.Payload{"scope": "read  write admin"}}
+
+	assert.DeepEqual(t, accesstoken.Scopes(token), []string{"read", "write", "admin"})
+	assert.Assert(t, accesstoken.HasScopes(token, "write", "read"))
+	assert.Assert(t, accesstoken.HasScopes(token))
+	assert.Assert(t, !accesstoken.HasScopes(token, "delete"))
+	assert.Assert(t, !accesstoken.HasScopes(jwt.Token{Payload: jwt.Payload{}}, "read"))
+}
@@ -0,0 +1,86 @@
+// Package accesstoken implements JWT profile for OAuth 2.0 access tokens, see RFC 9068.
+package accesstoken
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+// HeaderType is typ header of access tokens, see RFC 9068 section 2.1.
+const HeaderType = "at+jwt"
+
+var ErrIncompleteGrant = errors.New("subject, client id and audience are required")
+
+// Grant describes access token to issue.
+type Grant struct {
+	// Subject is resource owner id, or client id when token is issued to client itself
+	Subject  string
+	ClientID string
+	Audience []string
+	Scope    []string
+	// Claims are additional claims, e.g. auth_time, acr or roles, iss, exp, iat and jti are ignored
+	Claims jwt.Payload
+}
+
+type IssuerOption func(i *Issuer)
+
+// WithClock sets clock used for iat and exp claims.
+func WithClock(clock jwt.Clock) IssuerOption {
+	return func(i *Issuer) {
+		i.clock = clock
+	}
+}
+
+// Issuer creates access tokens with at+jwt typ and iss, exp, aud, sub, client_id, iat and jti claims.
+type Issuer struct {
+	creator *jwt.JwtTokenCreator
+	clock   jwt.Clock
+}
+
+func NewIssuer(signingMethod jwt.SigningMethod, issuer string, ttl time.Duration, opts ...IssuerOption) *Issuer {
+	i := &Issuer{clock: jwt.SystemClock}
+	for _, opt := range opts {
+		opt(i)
+	}
+	i.creator = jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod,
+		jwt.WithHeaderType(HeaderType),
+		jwt.WithIssuer(issuer),
+		jwt.WithTTL(ttl),
+		jwt.WithTokenID(),
+		jwt.WithClock(i.clock),
+	)
+	return i
+}
+
+func (i *Issuer) Issue(ctx context.Context, grant Grant) (string, error) {
+	if grant.Subject == "" || grant.ClientID == "" || len(grant.Audience) == 0 {
+		return "", ErrIncompleteGrant
+	}
+
+	payload := make(jwt.Payload, len(grant.Claims)+5)
+	for claim, value := range grant.Claims {
+		switch claim {
+		case "iss", "exp", "iat", "jti":
+		default:
+			payload[claim] = value
+		}
+	}
+
+	payload["sub"] = grant.Subject
+	payload["client_id"] = grant.ClientID
+	if len(grant.Audience) == 1 {
+		payload["aud"] = grant.Audience[0]
+	} else {
+		payload["aud"] = grant.Audience
+	}
+	if len(grant.Scope) != 0 {
+		payload["scope"] = strings.Join(grant.Scope, " ")
+	}
+
+	return i.creator.CreateTokenContext(ctx, payload)
+}
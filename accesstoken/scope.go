@@ -0,0 +1,37 @@
+package accesstoken
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+var ErrInsufficientScope jwt.TokenInvalidError = "insufficient_scope"
+
+// Scopes returns space separated scope claim of token.
+func Scopes(token jwt.Token) []string {
+	scope, _ := token.Payload["scope"].(string)
+	return strings.Fields(scope)
+}
+
+// HasScopes reports whether token has every scope.
+func HasScopes(token jwt.Token, scopes ...string) bool {
+	granted := Scopes(token)
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// RequireScopes returns validator that rejects tokens without every scope with ErrInsufficientScope.
+func RequireScopes(scopes ...string) jwt.TokenValidator {
+	return jwt.TokenValidatorFunc(func(token jwt.Token) error {
+		if !HasScopes(token, scopes...) {
+			return ErrInsufficientScope
+		}
+		return nil
+	})
+}
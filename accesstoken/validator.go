@@ -0,0 +1,88 @@
+package accesstoken
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+var (
+	ErrInvalidType     jwt.TokenInvalidError = "invalid_token_type"
+	ErrInvalidIssuer   jwt.TokenInvalidError = "invalid_issuer"
+	ErrInvalidAudience jwt.TokenInvalidError = "invalid_audience"
+	ErrMissingClaim    jwt.TokenInvalidError = "missing_claim"
+)
+
+type ValidatorOption func(v *Validator)
+
+// WithValidatorClock sets clock used to check exp claim.
+func WithValidatorClock(clock jwt.Clock) ValidatorOption {
+	return func(v *Validator) {
+		v.clock = clock
+	}
+}
+
+// Validator validates access tokens on resource server, see RFC 9068 section 4.
+// Tokens without at+jwt typ are rejected, so ID tokens and other tokens of the issuer can't be used as access tokens.
+type Validator struct {
+	parser   jwt.ContextTokenParser
+	issuer   string
+	audience string
+	clock    jwt.Clock
+}
+
+// NewValidator returns validator of tokens verified by parser, issued by issuer for audience, e.g. resource server url.
+func NewValidator(parser jwt.ContextTokenParser, issuer, audience string, opts ...ValidatorOption) *Validator {
+	v := &Validator{
+		parser:   parser,
+		issuer:   issuer,
+		audience: audience,
+		clock:    jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Validate parses and validates access token, tokenValidators are applied after the profile checks, e.g. RequireScopes.
+func (v *Validator) Validate(ctx context.Context, accessToken string, tokenValidators ...jwt.TokenValidator) (jwt.Token, error) {
+	tokenValidators = append([]jwt.TokenValidator{jwt.TokenValidatorFunc(v.validate)}, tokenValidators...)
+	return jwt.ParseAndValidateTokenContext(ctx, v.parser, accessToken, tokenValidators...)
+}
+
+func (v *Validator) validate(token jwt.Token) error {
+	// application/at+jwt is allowed as media type with application/ prefix, see RFC 7515 section 4.1.9
+	typ := strings.ToLower(token.Header.Type)
+	if typ != HeaderType && typ != "application/"+HeaderType {
+		return ErrInvalidType
+	}
+
+	if iss, _ := token.Payload["iss"].(string); iss != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if audience, _ := jwt.PayloadAudience(token.Payload); !slices.Contains(audience, v.audience) {
+		return ErrInvalidAudience
+	}
+
+	exp, ok := jwt.PayloadTime(token.Payload, "exp")
+	if !ok {
+		return jwt.ErrNoExpiration
+	}
+	if !v.clock.Now().Before(exp) {
+		return jwt.ErrTokenExpired
+	}
+
+	if _, ok := jwt.PayloadTime(token.Payload, "iat"); !ok {
+		return ErrMissingClaim
+	}
+	for _, claim := range []string{"sub", "client_id", "jti"} {
+		if value, _ := token.Payload[claim].(string); value == "" {
+			return ErrMissingClaim
+		}
+	}
+
+	return nil
+}
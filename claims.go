@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+func payloadString(payload Payload, claim string) (string, bool) {
+	value, ok := payload[claim].(string)
+	if !ok || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// PayloadTime returns NumericDate claim, e.g. exp, iat or nbf, seconds could be any JSON number.
+func PayloadTime(payload Payload, claim string) (time.Time, bool) {
+	switch value := payload[claim].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case int64:
+		return time.Unix(value, 0), true
+	case int:
+		return time.Unix(int64(value), 0), true
+	case json.Number:
+		seconds, err := value.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(seconds, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// PayloadAudience returns aud claim, single audience could be a string.
+// Claim with value other than string or array of strings is invalid.
+func PayloadAudience(payload Payload) ([]string, bool) {
+	switch aud := payload["aud"].(type) {
+	case string:
+		return []string{aud}, true
+	case []string:
+		return aud, true
+	case []any:
+		audience := make([]string, 0, len(aud))
+		for _, value := range aud {
+			value, ok := value.(string)
+			if !ok {
+				return nil, false
+			}
+			audience = append(audience, value)
+		}
+		return audience, true
+	default:
+		return nil, false
+	}
+}
+
+type registeredClaims struct {
+	clock     Clock
+	ttl       time.Duration
+	notBefore *time.Duration
+	issuer    string
+	audience  []string
+	tokenID   bool
+}
+
+func (c *registeredClaims) empty() bool {
+	return c.ttl == 0 && c.notBefore == nil && c.issuer == "" && len(c.audience) == 0 && !c.tokenID
+}
+
+// fill returns copy of the payload with absent registered claims set, payload itself is not modified.
+func (c *registeredClaims) fill(payload Payload) (Payload, error) {
+	if c.empty() {
+		return payload, nil
+	}
+
+	result := make(Payload, len(payload)+6)
+	for claim, value := range payload {
+		result[claim] = value
+	}
+
+	now := c.clock.Now()
+	if c.ttl > 0 || c.notBefore != nil {
+		setAbsent(result, "iat", now.Unix())
+	}
+	if c.ttl > 0 {
+		setAbsent(result, "exp", now.Add(c.ttl).Unix())
+	}
+	if c.notBefore != nil {
+		setAbsent(result, "nbf", now.Add(*c.notBefore).Unix())
+	}
+	if c.issuer != "" {
+		setAbsent(result, "iss", c.issuer)
+	}
+	switch len(c.audience) {
+	case 0:
+	case 1:
+		setAbsent(result, "aud", c.audience[0])
+	default:
+		setAbsent(result, "aud", c.audience)
+	}
+	// jti is set by every registered claims option, so tokens of the creator can be revoked and checked for replay
+	if _, ok := result["jti"]; !ok {
+		jti, err := NewTokenID()
+		if err != nil {
+			return nil, err
+		}
+		result["jti"] = jti
+	}
+	return result, nil
+}
+
+func setAbsent(payload Payload, claim string, value any) {
+	if _, ok := payload[claim]; !ok {
+		payload[claim] = value
+	}
+}
+
+// NewTokenID returns random base64url token id, it is used for jti of created tokens, e.g. by WithTokenID and TokenPairIssuer.
+func NewTokenID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("failed generate token id, %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
@@ -0,0 +1,235 @@
+package clientassertion_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/clientassertion"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+const tokenEndpoint = "https://as.example.com/token"
+
+func Test_Creator(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	signingMethod := signingmethods.NewHS256("client secret")
+
+	creator := clientassertion.NewCreator(signingMethod, "client",
+		clientassertion.WithClock(jwt.ClockFunc(func() time.Time { return now })),
+		clientassertion.WithLifetime(30*time.Second),
+	)
+
+	values, err := creator.FormValues(context.Background(), tokenEndpoint)
+	assert.NilError(t, err)
+	assert.Equal(t, values.Get("client_assertion_type"), "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+
+	token, err := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod).ParseToken(values.Get("client_assertion"))
+	assert.NilError(t, err)
+
+	jti := token.Payload["jti"]
+	assert.Assert(t, jti != "")
+	assert.DeepEqual(t, token.Payload, jwt.Payload{
+		"iss": "client",
+		"sub": "client",
+		"aud": tokenEndpoint,
+		"iat": float64(now.Unix()),
+		"exp": float64(now.Add(30 * time.Second).Unix()),
+		"jti": jti,
+	})
+
+	other, err := creator.CreateAssertion(context.Background(), tokenEndpoint)
+	assert.NilError(t, err)
+	assert.Assert(t, other != values.Get("client_assertion"))
+}
+
+func Test_Verifier(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	secret := signingmethods.NewHS256("client secret")
+
+	resolver := clientassertion.ClientKeyResolverFunc(func(ctx context.Context, clientID string) (jwt.SigningMethod, error) {
+		switch clientID {
+		case "private-key-client":
+			return signingmethods.NewES256Verifier(keys.PublicKey()), nil
+		case "secret-client", "other-secret-client", "client", "client shared":
+			return secret, nil
+		default:
+			return nil, clientassertion.ErrUnknownClient
+		}
+	})
+	verifier := clientassertion.NewVerifier(resolver, []string{tokenEndpoint, "https://as.example.com"})
+
+	assertion := func(creator *clientassertion.Creator) string {
+		assertion, err := creator.CreateAssertion(ctx, tokenEndpoint)
+		assert.NilError(t, err)
+		return assertion
+	}
+	custom := func(signingMethod jwt.SigningMethod, payload jwt.Payload) string {
+		now := time.Now()
+		claims := jwt.Payload{
+			"iss": "secret-client",
+			"sub": "secret-client",
+			"aud": tokenEndpoint,
+			"jti": now.String(),
+			"iat": now.Unix(),
+			"exp": now.Add(time.Minute).Unix(),
+		}
+		for claim, value := range payload {
+			if value == nil {
+				delete(claims, claim)
+				continue
+			}
+			claims[claim] = value
+		}
+		assertion, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod).CreateToken(claims)
+		assert.NilError(t, err)
+		return assertion
+	}
+
+	privateKeyCreator := clientassertion.NewCreator(keys.SigningMethod(), "private-key-client")
+	replayed := assertion(privateKeyCreator)
+
+	cases := []struct {
+		name             string
+		assertion        string
+		expectedClientID string
+		expectedErr      error
+	}{
+		{
+			name:             "private_key_jwt",
+			assertion:        replayed,
+			expectedClientID: "private-key-client",
+		},
+		{
+			name:             "client_secret_jwt",
+			assertion:        assertion(clientassertion.NewCreator(secret, "secret-client")),
+			expectedClientID: "secret-client",
+		},
+		{
+			name:             "issuer audience",
+			assertion:        custom(secret, jwt.Payload{"aud": []string{"https://as.example.com"}}),
+			expectedClientID: "secret-client",
+		},
+		{
+			name:        "replayed",
+			assertion:   replayed,
+			expectedErr: jwt.ErrTokenReplayed,
+		},
+		{
+			name:        "unknown client",
+			assertion:   assertion(clientassertion.NewCreator(secret, "other")),
+			expectedErr: clientassertion.ErrUnknownClient,
+		},
+		{
+			name:        "signed with other key",
+			assertion:   assertion(clientassertion.NewCreator(signingmethods.NewHS256("other secret"), "secret-client")),
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+		{
+			name:        "sub is not client",
+			assertion:   custom(secret, jwt.Payload{"sub": "user"}),
+			expectedErr: clientassertion.ErrInvalidSubject,
+		},
+		{
+			name:        "other audience",
+			assertion:   custom(secret, jwt.Payload{"aud": "https://other.example.com/token"}),
+			expectedErr: clientassertion.ErrInvalidAudience,
+		},
+		{
+			name:        "expired",
+			assertion:   custom(secret, jwt.Payload{"exp": time.Now().Add(-time.Second).Unix()}),
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:        "no exp",
+			assertion:   custom(secret, jwt.Payload{"exp": nil}),
+			expectedErr: jwt.ErrNoExpiration,
+		},
+		{
+			name:        "not yet valid",
+			assertion:   custom(secret, jwt.Payload{"nbf": time.Now().Add(time.Minute).Unix()}),
+			expectedErr: jwt.ErrTokenNotYetValid,
+		},
+		{
+			name:        "long lifetime",
+			assertion:   assertion(clientassertion.NewCreator(secret, "secret-client", clientassertion.WithLifetime(time.Hour))),
+			expectedErr: clientassertion.ErrLifetimeTooLong,
+		},
+		{
+			name:        "long lifetime without iat",
+			assertion:   custom(secret, jwt.Payload{"iat": nil, "exp": time.Now().Add(time.Hour).Unix()}),
+			expectedErr: clientassertion.ErrLifetimeTooLong,
+		},
+		{
+			name:        "no jti",
+			assertion:   custom(secret, jwt.Payload{"jti": nil}),
+			expectedErr: jwt.ErrNoTokenID,
+		},
+		{
+			name:        "not jwt",
+			assertion:   "assertion",
+			expectedErr: jwt.ErrBadToken,
+		},
+	}
+
+	for _, cs := range cases {
+		clientID, err := verifier.Verify(ctx, cs.assertion)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		assert.Equal(t, clientID, cs.expectedClientID, cs.name)
+	}
+
+	// jti is unique per client
+	clientID, err := verifier.Verify(ctx, custom(secret, jwt.Payload{"jti": "shared"}))
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "secret-client")
+	clientID, err = verifier.Verify(ctx, custom(secret, jwt.Payload{"jti": "shared", "iss": "other-secret-client", "sub": "other-secret-client"}))
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "other-secret-client")
+
+	// iss and jti are not concatenated into the same id
+	clientID, err = verifier.Verify(ctx, custom(secret, jwt.Payload{"jti": "shared jti", "iss": "client", "sub": "client"}))
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "client")
+	clientID, err = verifier.Verify(ctx, custom(secret, jwt.Payload{"jti": "jti", "iss": "client shared", "sub": "client shared"}))
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "client shared")
+}
+
+func Test_Verifier_VerifyRequest(t *testing.T) {
+	ctx := context.Background()
+	secret := signingmethods.NewHS256("client secret")
+	resolver := clientassertion.ClientKeyResolverFunc(func(ctx context.Context, clientID string) (jwt.SigningMethod, error) {
+		return secret, nil
+	})
+	verifier := clientassertion.NewVerifier(resolver, []string{tokenEndpoint})
+	creator := clientassertion.NewCreator(secret, "client")
+
+	form := func() url.Values {
+		values, err := creator.FormValues(ctx, tokenEndpoint)
+		assert.NilError(t, err)
+		return values
+	}
+
+	values := form()
+	clientID, err := verifier.VerifyRequest(ctx, values.Get("client_assertion_type"), values.Get("client_assertion"), "")
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "client")
+
+	values = form()
+	clientID, err = verifier.VerifyRequest(ctx, values.Get("client_assertion_type"), values.Get("client_assertion"), "client")
+	assert.NilError(t, err)
+	assert.Equal(t, clientID, "client")
+
+	values = form()
+	_, err = verifier.VerifyRequest(ctx, values.Get("client_assertion_type"), values.Get("client_assertion"), "other")
+	assert.ErrorIs(t, err, clientassertion.ErrClientIDMismatch)
+
+	_, err = verifier.VerifyRequest(ctx, "urn:ietf:params:oauth:client-assertion-type:saml2-bearer", form().Get("client_assertion"), "")
+	assert.ErrorIs(t, err, clientassertion.ErrUnsupportedType)
+}
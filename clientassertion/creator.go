@@ -0,0 +1,84 @@
+// Package clientassertion implements JWT client authentication to OAuth 2.0 authorization server,
+// private_key_jwt and client_secret_jwt methods, see RFC 7523 and OpenID Connect Core 1.0 section 9.
+package clientassertion
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+// AssertionType is client_assertion_type of JWT client assertions.
+const AssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// DefaultLifetime is lifetime of assertions created by Creator.
+const DefaultLifetime = time.Minute
+
+type CreatorOption func(c *creatorConfig)
+
+type creatorConfig struct {
+	lifetime time.Duration
+	clock    jwt.Clock
+}
+
+// WithLifetime sets lifetime of assertion, DefaultLifetime is used by default.
+func WithLifetime(lifetime time.Duration) CreatorOption {
+	return func(c *creatorConfig) {
+		c.lifetime = lifetime
+	}
+}
+
+// WithClock sets clock used for iat and exp claims.
+func WithClock(clock jwt.Clock) CreatorOption {
+	return func(c *creatorConfig) {
+		c.clock = clock
+	}
+}
+
+// Creator creates client assertions with iss and sub set to client id, token endpoint as aud, short exp and unique jti.
+type Creator struct {
+	clientID string
+	creator  *jwt.JwtTokenCreator
+}
+
+// NewCreator returns creator of assertions signed with signingMethod,
+// e.g. RS256 with client private key for private_key_jwt or HS256 with client secret for client_secret_jwt.
+func NewCreator(signingMethod jwt.SigningMethod, clientID string, opts ...CreatorOption) *Creator {
+	config := creatorConfig{lifetime: DefaultLifetime, clock: jwt.SystemClock}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Creator{
+		clientID: clientID,
+		creator: jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod,
+			jwt.WithIssuer(clientID),
+			jwt.WithTTL(config.lifetime),
+			jwt.WithTokenID(),
+			jwt.WithClock(config.clock),
+		),
+	}
+}
+
+// CreateAssertion returns assertion for token endpoint, it must be used only once.
+func (c *Creator) CreateAssertion(ctx context.Context, tokenEndpoint string) (string, error) {
+	return c.creator.CreateTokenContext(ctx, jwt.Payload{
+		"sub": c.clientID,
+		"aud": tokenEndpoint,
+	})
+}
+
+// FormValues returns client_assertion_type and client_assertion parameters of token request with new assertion.
+func (c *Creator) FormValues(ctx context.Context, tokenEndpoint string) (url.Values, error) {
+	assertion, err := c.CreateAssertion(ctx, tokenEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{
+		"client_assertion_type": {AssertionType},
+		"client_assertion":      {assertion},
+	}, nil
+}
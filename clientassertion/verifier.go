@@ -0,0 +1,212 @@
+package clientassertion
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+var (
+	ErrUnknownClient    jwt.TokenInvalidError = "unknown_client"
+	ErrInvalidSubject   jwt.TokenInvalidError = "invalid_subject"
+	ErrInvalidAudience  jwt.TokenInvalidError = "invalid_audience"
+	ErrLifetimeTooLong  jwt.TokenInvalidError = "lifetime_too_long"
+	ErrClientIDMismatch jwt.TokenInvalidError = "client_id_mismatch"
+	ErrUnsupportedType  jwt.TokenInvalidError = "unsupported_assertion_type"
+)
+
+// DefaultMaxLifetime is maximum lifetime of assertions accepted by Verifier.
+const DefaultMaxLifetime = 5 * time.Minute
+
+// ClientKeyResolver returns signing method that verifies assertions of client, e.g. with client registered public key.
+// ErrUnknownClient should be returned for unknown client.
+type ClientKeyResolver interface {
+	ClientSigningMethod(ctx context.Context, clientID string) (jwt.SigningMethod, error)
+}
+
+type ClientKeyResolverFunc func(ctx context.Context, clientID string) (jwt.SigningMethod, error)
+
+func (f ClientKeyResolverFunc) ClientSigningMethod(ctx context.Context, clientID string) (jwt.SigningMethod, error) {
+	return f(ctx, clientID)
+}
+
+type VerifierOption func(v *Verifier)
+
+// WithMaxLifetime sets maximum lifetime of assertion, DefaultMaxLifetime is used by default.
+func WithMaxLifetime(maxLifetime time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxLifetime = maxLifetime
+	}
+}
+
+// WithReplayStore sets store of assertion ids, jwt.MemoryReplayStore is used by default.
+// Store shared between instances is required when several servers accept assertions.
+func WithReplayStore(store jwt.ReplayStore) VerifierOption {
+	return func(v *Verifier) {
+		v.replayStore = store
+	}
+}
+
+// WithVerifierClock sets clock used to check exp, nbf and iat claims.
+func WithVerifierClock(clock jwt.Clock) VerifierOption {
+	return func(v *Verifier) {
+		v.clock = clock
+	}
+}
+
+// Verifier verifies client assertions on authorization server, see RFC 7523 section 3.
+// Every assertion is accepted only once.
+type Verifier struct {
+	audience    []string
+	resolver    ClientKeyResolver
+	maxLifetime time.Duration
+	replayStore jwt.ReplayStore
+	clock       jwt.Clock
+}
+
+// NewVerifier returns verifier of assertions with aud of one of audience values, e.g. token endpoint url and issuer.
+func NewVerifier(resolver ClientKeyResolver, audience []string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		audience:    audience,
+		resolver:    resolver,
+		maxLifetime: DefaultMaxLifetime,
+		clock:       jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.replayStore == nil {
+		v.replayStore = jwt.NewMemoryReplayStore()
+	}
+	return v
+}
+
+// Verify verifies assertion and returns authenticated client id.
+func (v *Verifier) Verify(ctx context.Context, assertion string) (clientID string, err error) {
+	clientID, err = unverifiedIssuer(assertion)
+	if err != nil {
+		return "", err
+	}
+
+	signingMethod, err := v.resolver.ClientSigningMethod(ctx, clientID)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = jwt.ParseAndValidateTokenContext(ctx,
+		jwt.NewTokenParser(base64.RawURLEncoding, signingMethod),
+		assertion,
+		jwt.NewBackgroundTokenValidator(jwt.ContextTokenValidatorFunc(v.validate)),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return clientID, nil
+}
+
+// VerifyRequest verifies client_assertion_type and client_assertion parameters of token request,
+// client_id parameter is optional, when it is not empty it must be equal to the assertion client.
+func (v *Verifier) VerifyRequest(ctx context.Context, assertionType, assertion, clientID string) (string, error) {
+	if assertionType != AssertionType {
+		return "", ErrUnsupportedType
+	}
+	assertionClientID, err := v.Verify(ctx, assertion)
+	if err != nil {
+		return "", err
+	}
+	if clientID != "" && clientID != assertionClientID {
+		return "", ErrClientIDMismatch
+	}
+	return assertionClientID, nil
+}
+
+func (v *Verifier) validate(ctx context.Context, token jwt.Token) error {
+	iss, _ := token.Payload["iss"].(string)
+	if sub, _ := token.Payload["sub"].(string); sub != iss {
+		return ErrInvalidSubject
+	}
+
+	audience, _ := jwt.PayloadAudience(token.Payload)
+	if !slices.ContainsFunc(audience, func(aud string) bool {
+		return slices.Contains(v.audience, aud)
+	}) {
+		return ErrInvalidAudience
+	}
+
+	now := v.clock.Now()
+	exp, ok := jwt.PayloadTime(token.Payload, "exp")
+	if !ok {
+		return jwt.ErrNoExpiration
+	}
+	if !now.Before(exp) {
+		return jwt.ErrTokenExpired
+	}
+	if nbf, ok := jwt.PayloadTime(token.Payload, "nbf"); ok && now.Before(nbf) {
+		return jwt.ErrTokenNotYetValid
+	}
+
+	// long-lived assertion is rejected even without iat, since jti has to be remembered until exp
+	if exp.Sub(now) > v.maxLifetime {
+		return ErrLifetimeTooLong
+	}
+	if iat, ok := jwt.PayloadTime(token.Payload, "iat"); ok && exp.Sub(iat) > v.maxLifetime {
+		return ErrLifetimeTooLong
+	}
+
+	jti, _ := token.Payload["jti"].(string)
+	if jti == "" {
+		return jwt.ErrNoTokenID
+	}
+
+	// jti is unique per client, see RFC 7523 section 3
+	return v.record(ctx, replayID(iss, jti), exp)
+}
+
+// replayID returns JSON array of iss and jti, so distinct pairs never share id.
+func replayID(iss, jti string) string {
+	id, _ := json.Marshal([]string{iss, jti})
+	return string(id)
+}
+
+func (v *Verifier) record(ctx context.Context, id string, expiresAt time.Time) error {
+	firstUse, err := jwt.RecordTokenID(ctx, v.replayStore, id, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed record assertion id, %w", err)
+	}
+	if !firstUse {
+		return jwt.ErrTokenReplayed
+	}
+	return nil
+}
+
+func unverifiedIssuer(assertion string) (string, error) {
+	rawToken, err := jwt.ParseRawToken(assertion)
+	if err != nil {
+		return "", err
+	}
+
+	var claims struct {
+		Iss string `json:"iss"`
+	}
+	data, err := base64.RawURLEncoding.DecodeString(rawToken.Payload())
+	if err == nil {
+		err = json.Unmarshal(data, &claims)
+	}
+	if err != nil {
+		return "", &jwt.ParseError{
+			Stage:   jwt.StageDecodePayload,
+			Segment: jwt.PayloadSegment,
+			Err:     fmt.Errorf("%w: %w", jwt.ErrBadToken, err),
+		}
+	}
+	if claims.Iss == "" {
+		return "", ErrUnknownClient
+	}
+	return claims.Iss, nil
+}
@@ -0,0 +1,15 @@
+package jwt
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+var SystemClock ClockFunc = time.Now
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+func runDecode(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := newFlagSet("decode", stderr)
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	token, err := readToken(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	rawToken, err := jwt.ParseRawToken(token)
+	if err != nil {
+		return err
+	}
+
+	header, err := decodeSegment(rawToken.Header())
+	if err != nil {
+		return fmt.Errorf("failed decode header, %w", err)
+	}
+	payload, err := decodeSegment(rawToken.Payload())
+	if err != nil {
+		return fmt.Errorf("failed decode payload, %w", err)
+	}
+
+	fmt.Fprintf(stdout, "header:\n%s\npayload:\n%s\n", header, payload)
+	printTimes(stdout, payload)
+	fmt.Fprintln(stdout, "signature: NOT VERIFIED")
+
+	return nil
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, err
+	}
+
+	var pretty bytes.Buffer
+	err = json.Indent(&pretty, data, "", "  ")
+	if err != nil {
+		return nil, jwt.ErrUnmarshalToken
+	}
+
+	return pretty.Bytes(), nil
+}
+
+func printTimes(out io.Writer, payload []byte) {
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+		Nbf *json.Number `json:"nbf"`
+		Iat *json.Number `json:"iat"`
+	}
+	if json.Unmarshal(payload, &claims) != nil {
+		return
+	}
+
+	current := now()
+	if exp, ok := claimTime(claims.Exp); ok {
+		if exp.Before(current) {
+			fmt.Fprintf(out, "exp: %s, EXPIRED %s ago\n", exp.Format(time.RFC3339), current.Sub(exp).Round(time.Second))
+		} else {
+			fmt.Fprintf(out, "exp: %s, expires in %s\n", exp.Format(time.RFC3339), exp.Sub(current).Round(time.Second))
+		}
+	} else {
+		fmt.Fprintln(out, "exp: NONE, token never expires")
+	}
+	if nbf, ok := claimTime(claims.Nbf); ok {
+		if nbf.After(current) {
+			fmt.Fprintf(out, "nbf: %s, NOT VALID YET for %s\n", nbf.Format(time.RFC3339), nbf.Sub(current).Round(time.Second))
+		} else {
+			fmt.Fprintf(out, "nbf: %s\n", nbf.Format(time.RFC3339))
+		}
+	}
+	if iat, ok := claimTime(claims.Iat); ok {
+		fmt.Fprintf(out, "iat: %s\n", iat.Format(time.RFC3339))
+	}
+}
+
+func claimTime(value *json.Number) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	seconds, err := value.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0).UTC(), true
+}
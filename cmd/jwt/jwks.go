@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var errUnknownKidSource = errors.New("-kid must be file or thumbprint")
+
+// runJWKS prints JWK Set of public keys from *.pem files of the directory sorted by file name.
+// Private keys are skipped, so keygen output directory is accepted as is.
+func runJWKS(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	flags := newFlagSet("jwks", stderr)
+	use := flags.String("use", "sig", "use parameter of the keys, omitted when empty")
+	kidSource := flags.String("kid", "file", "key id source: file name without .pub.pem or .pem suffix, or thumbprint")
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+	if *kidSource != "file" && *kidSource != "thumbprint" {
+		return errUnknownKidSource
+	}
+
+	dir := flags.Arg(0)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed read keys directory, %w", err)
+	}
+
+	set := jwk.Set{Keys: []jwk.Key{}}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed read key, %w", err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return fmt.Errorf("%s: %w", entry.Name(), signingmethods.ErrKeyMustBePEMEncoded)
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+
+		public, err := parsePublicKey(block)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		key, err := jwk.NewPublicKey(public)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		key.Use = *use
+		if *kidSource == "thumbprint" {
+			thumbprint, err := key.Thumbprint(crypto.SHA256)
+			if err != nil {
+				return err
+			}
+			key.Kid = base64.RawURLEncoding.EncodeToString(thumbprint)
+		} else {
+			key.Kid = strings.TrimSuffix(strings.TrimSuffix(entry.Name(), ".pem"), ".pub")
+		}
+
+		set.Keys = append(set.Keys, key)
+	}
+
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s\n", data)
+
+	return nil
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err == nil {
+		return public, nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err == nil {
+		return cert.PublicKey, nil
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/amidgo/jwt/jwk"
+)
+
+// random is replaced in tests, RSA and ECDSA key generation is not deterministic anyway.
+var random io.Reader = rand.Reader
+
+var errUnknownFormat = errors.New("-format must be pem or jwk")
+
+type keyFile struct {
+	name string
+	perm os.FileMode
+	data []byte
+}
+
+func runKeygen(args []string, _ io.Reader, stdout, stderr io.Writer) error {
+	flags := newFlagSet("keygen", stderr)
+	keyType := flags.String("type", "rsa", "key type: rsa, ec, ed25519 or hmac")
+	bits := flags.Int("bits", 0, "RSA modulus size, default 2048, or HMAC secret size, default 256")
+	crv := flags.String("crv", "P-256", "EC curve: P-256, P-384 or P-521")
+	kid := flags.String("kid", "", "key id, default is base64url SHA-256 JWK thumbprint")
+	format := flags.String("format", "pem", "output format: pem or jwk")
+	out := flags.String("out", "", "directory to write <kid> key files, stdout when empty")
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errUsage
+	}
+	if *format != "pem" && *format != "jwk" {
+		return errUnknownFormat
+	}
+
+	var private crypto.PrivateKey
+	switch *keyType {
+	case "rsa":
+		size := *bits
+		if size == 0 {
+			size = 2048
+		}
+		private, err = rsa.GenerateKey(random, size)
+	case "ec":
+		var curve elliptic.Curve
+		curve, err = curveByName(*crv)
+		if err == nil {
+			private, err = ecdsa.GenerateKey(curve, random)
+		}
+	case "ed25519":
+		_, private, err = ed25519.GenerateKey(random)
+	case "hmac":
+		size := *bits
+		if size == 0 {
+			size = 256
+		}
+		private, err = generateSecret(size)
+	default:
+		return fmt.Errorf("unsupported key type %q", *keyType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed generate key, %w", err)
+	}
+
+	files, err := encodeKey(private, *kid, *format)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		for _, file := range files {
+			stdout.Write(file.data)
+			if !bytes.HasSuffix(file.data, []byte("\n")) {
+				fmt.Fprintln(stdout)
+			}
+		}
+		return nil
+	}
+	for _, file := range files {
+		path := filepath.Join(*out, file.name)
+		err = os.WriteFile(path, file.data, file.perm)
+		if err != nil {
+			return fmt.Errorf("failed write key, %w", err)
+		}
+		fmt.Fprintln(stdout, path)
+	}
+
+	return nil
+}
+
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, jwk.ErrUnsupportedCurve
+	}
+}
+
+func generateSecret(bits int) ([]byte, error) {
+	if bits < 8 || bits%8 != 0 {
+		return nil, errors.New("HMAC secret size must be a multiple of 8")
+	}
+	secret := make([]byte, bits/8)
+	_, err := io.ReadFull(random, secret)
+	return secret, err
+}
+
+// encodeKey returns private and public key files, HMAC secret has the only file.
+// PEM HMAC secret is base64url text of the secret, sign and verify commands decode it, so it is the same key as JWK.
+func encodeKey(private crypto.PrivateKey, kid, format string) ([]keyFile, error) {
+	privateJWK, err := privateKeyJWK(private)
+	if err != nil {
+		return nil, err
+	}
+	if kid == "" {
+		thumbprint, err := privateJWK.Thumbprint(crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		kid = base64.RawURLEncoding.EncodeToString(thumbprint)
+	}
+	privateJWK.Kid = kid
+
+	if format == "jwk" {
+		privateData, err := json.MarshalIndent(privateJWK, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files := []keyFile{{name: kid + ".json", perm: 0o600, data: append(privateData, '\n')}}
+		if privateJWK.Kty == "oct" {
+			return files, nil
+		}
+
+		publicData, err := json.MarshalIndent(privateJWK.Public(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(files, keyFile{name: kid + ".pub.json", perm: 0o644, data: append(publicData, '\n')}), nil
+	}
+
+	var block *pem.Block
+	var public crypto.PublicKey
+	switch private := private.(type) {
+	case []byte:
+		secret := base64.RawURLEncoding.EncodeToString(private)
+		return []keyFile{{name: kid + ".key", perm: 0o600, data: []byte(secret)}}, nil
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}
+		public = &private.PublicKey
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(private)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+		public = &private.PublicKey
+	case ed25519.PrivateKey:
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		public = private.Public()
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return []keyFile{
+		{name: kid + ".pem", perm: 0o600, data: pem.EncodeToMemory(block)},
+		{name: kid + ".pub.pem", perm: 0o644, data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})},
+	}, nil
+}
+
+func privateKeyJWK(private crypto.PrivateKey) (jwk.Key, error) {
+	switch private := private.(type) {
+	case []byte:
+		return jwk.NewSymmetricKey(private), nil
+	case *rsa.PrivateKey:
+		return jwk.NewRSAPrivateKey(private), nil
+	case *ecdsa.PrivateKey:
+		return jwk.NewECPrivateKey(private)
+	case ed25519.PrivateKey:
+		return jwk.NewOKPPrivateKey(private), nil
+	default:
+		return jwk.Key{}, jwk.ErrUnsupportedKeyType
+	}
+}
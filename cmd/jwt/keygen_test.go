@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func setRandom(t *testing.T) {
+	t.Helper()
+
+	previous := random
+	random = zeroReader{}
+	t.Cleanup(func() { random = previous })
+}
+
+func TestKeygenGolden(t *testing.T) {
+	setRandom(t)
+
+	cases := []struct {
+		golden string
+		args   []string
+	}{
+		{golden: "keygen-ed25519-pem.golden", args: []string{"keygen", "-type", "ed25519", "-kid", "ed"}},
+		{golden: "keygen-ed25519-jwk.golden", args: []string{"keygen", "-type", "ed25519", "-format", "jwk"}},
+		{golden: "keygen-hmac-jwk.golden", args: []string{"keygen", "-type", "hmac", "-format", "jwk", "-kid", "hs"}},
+		{golden: "keygen-hmac-pem.golden", args: []string{"keygen", "-type", "hmac", "-bits", "128"}},
+	}
+
+	for _, cs := range cases {
+		stdout, stderr, code := runCommand(t, "", cs.args...)
+		assert.Equal(t, code, 0, stderr)
+		golden.Assert(t, stdout, cs.golden)
+	}
+}
+
+func TestKeygenSignVerify(t *testing.T) {
+	cases := []struct {
+		alg  string
+		args []string
+	}{
+		{alg: "RS256", args: []string{"-type", "rsa"}},
+		{alg: "ES384", args: []string{"-type", "ec", "-crv", "P-384"}},
+		{alg: "HS256", args: []string{"-type", "hmac"}},
+	}
+
+	for _, cs := range cases {
+		dir := t.TempDir()
+		stdout, stderr, code := runCommand(t, "", append([]string{"keygen", "-kid", "key", "-out", dir}, cs.args...)...)
+		assert.Equal(t, code, 0, stderr)
+
+		privateFile := filepath.Join(dir, "key.pem")
+		publicFile := filepath.Join(dir, "key.pub.pem")
+		if cs.alg == "HS256" {
+			privateFile = filepath.Join(dir, "key.key")
+			publicFile = privateFile
+			assert.Equal(t, stdout, privateFile+"\n")
+		} else {
+			assert.Equal(t, stdout, privateFile+"\n"+publicFile+"\n")
+		}
+
+		token, stderr, code := runCommand(t, "", "sign", "-alg", cs.alg, "-key", privateFile, "-claims", `{"sub":"user"}`)
+		assert.Equal(t, code, 0, stderr)
+
+		_, stderr, code = runCommand(t, token, "verify", "-alg", cs.alg, "-key", publicFile, "-exp=false")
+		assert.Equal(t, code, 0, stderr)
+	}
+}
+
+func TestKeygenHMACFormats(t *testing.T) {
+	setRandom(t)
+	dir := t.TempDir()
+
+	// same random gives the same secret in both formats
+	_, stderr, code := runCommand(t, "", "keygen", "-type", "hmac", "-kid", "hs", "-out", dir)
+	assert.Equal(t, code, 0, stderr)
+	_, stderr, code = runCommand(t, "", "keygen", "-type", "hmac", "-format", "jwk", "-kid", "hs", "-out", dir)
+	assert.Equal(t, code, 0, stderr)
+
+	// keygen stdout ends with newline
+	stdout, stderr, code := runCommand(t, "", "keygen", "-type", "hmac", "-kid", "hs")
+	assert.Equal(t, code, 0, stderr)
+	stdoutFile := filepath.Join(dir, "stdout.key")
+	assert.NilError(t, os.WriteFile(stdoutFile, []byte(stdout), 0o600))
+
+	pemFile, jwkFile := filepath.Join(dir, "hs.key"), filepath.Join(dir, "hs.json")
+	cases := []struct {
+		signKey   string
+		verifyKey string
+	}{
+		{signKey: pemFile, verifyKey: jwkFile},
+		{signKey: jwkFile, verifyKey: pemFile},
+		{signKey: stdoutFile, verifyKey: jwkFile},
+	}
+
+	for _, cs := range cases {
+		token, stderr, code := runCommand(t, "", "sign", "-alg", "HS256", "-key", cs.signKey, "-claims", `{"sub":"user"}`)
+		assert.Equal(t, code, 0, stderr)
+
+		_, stderr, code = runCommand(t, token, "verify", "-alg", "HS256", "-key", cs.verifyKey, "-exp=false")
+		assert.Equal(t, code, 0, cs.signKey+" "+cs.verifyKey+" "+stderr)
+	}
+
+	// secret is the decoded key, not base64url text of it
+	data, err := os.ReadFile(pemFile)
+	assert.NilError(t, err)
+	token, stderr, code := runCommand(t, "", "sign", "-alg", "HS256", "-key", pemFile, "-claims", `{"sub":"user"}`)
+	assert.Equal(t, code, 0, stderr)
+	_, _, code = runCommand(t, token, "verify", "-alg", "HS256", "-secret", string(data), "-exp=false")
+	assert.Assert(t, code != 0)
+}
+
+func TestKeygenInvalid(t *testing.T) {
+	_, stderr, code := runCommand(t, "", "keygen", "-type", "dsa")
+	assert.Equal(t, code, 1)
+	assert.Equal(t, stderr, "jwt: unsupported key type \"dsa\"\n")
+
+	_, stderr, code = runCommand(t, "", "keygen", "-type", "ec", "-crv", "P-192")
+	assert.Equal(t, code, 1)
+	assert.Assert(t, strings.Contains(stderr, "unsupported curve"), stderr)
+
+	_, _, code = runCommand(t, "", "keygen", "-format", "der")
+	assert.Equal(t, code, 1)
+}
+
+func TestJWKSGolden(t *testing.T) {
+	stdout, stderr, code := runCommand(t, "", "jwks", "testdata/keys")
+	assert.Equal(t, code, 0, stderr)
+	golden.Assert(t, stdout, "jwks.golden")
+
+	stdout, stderr, code = runCommand(t, "", "jwks", "-kid", "thumbprint", "-use", "", "testdata/keys")
+	assert.Equal(t, code, 0, stderr)
+	golden.Assert(t, stdout, "jwks-thumbprint.golden")
+}
+
+func TestJWKSFromKeygen(t *testing.T) {
+	dir := t.TempDir()
+	_, stderr, code := runCommand(t, "", "keygen", "-type", "ec", "-kid", "signing", "-out", dir)
+	assert.Equal(t, code, 0, stderr)
+
+	stdout, stderr, code := runCommand(t, "", "jwks", dir)
+	assert.Equal(t, code, 0, stderr)
+	assert.Assert(t, strings.Contains(stdout, `"kid": "signing"`), stdout)
+	assert.Equal(t, strings.Count(stdout, `"kty"`), 1)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var errNoKey = errors.New("-key or -secret is required")
+
+type keyFlags struct {
+	alg     string
+	keyFile string
+	secret  string
+}
+
+// signingMethod returns signing method of the alg, for RS and ES public key is accepted only for verification.
+// Key file is PEM, JWK or HMAC secret written by keygen, HMAC key file holds base64url secret and -secret is used as is.
+func (k keyFlags) signingMethod(verifyOnly bool) (jwt.SigningMethod, error) {
+	var key []byte
+	switch {
+	case k.secret != "":
+		key = []byte(k.secret)
+	case k.keyFile != "":
+		data, err := os.ReadFile(k.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed read key, %w", err)
+		}
+		data = bytes.TrimSpace(data)
+		if bytes.HasPrefix(data, []byte("{")) {
+			return jwkSigningMethod(k.alg, data, verifyOnly)
+		}
+		key = data
+		if strings.HasPrefix(k.alg, "HS") {
+			key, err = base64.RawURLEncoding.DecodeString(string(data))
+			if err != nil {
+				return nil, fmt.Errorf("HMAC key file must be base64url secret, %w", err)
+			}
+		}
+	default:
+		return nil, errNoKey
+	}
+
+	switch k.alg {
+	case "HS256", "HS384", "HS512":
+		return hmacSigningMethod(k.alg, key), nil
+	case "RS256", "RS384", "RS512":
+		return rsaSigningMethod(k.alg, key, verifyOnly)
+	case "ES256", "ES384", "ES512":
+		return ecSigningMethod(k.alg, key, verifyOnly)
+	default:
+		return nil, fmt.Errorf("unsupported alg %q", k.alg)
+	}
+}
+
+func hmacSigningMethod(alg string, secret []byte) jwt.SigningMethod {
+	switch alg {
+	case "HS256":
+		return signingmethods.NewHS256(string(secret))
+	case "HS384":
+		return signingmethods.NewHS384(string(secret))
+	default:
+		return signingmethods.NewHS512(string(secret))
+	}
+}
+
+// jwkSigningMethod returns signing method of JWK, RSA and EC keys are accepted only for verification.
+func jwkSigningMethod(alg string, data []byte, verifyOnly bool) (jwt.SigningMethod, error) {
+	var key jwk.Key
+	err := json.Unmarshal(data, &key)
+	if err != nil {
+		return nil, fmt.Errorf("failed decode jwk, %w", err)
+	}
+
+	if key.Kty == "oct" {
+		if !strings.HasPrefix(alg, "HS") {
+			return nil, fmt.Errorf("oct key can't be used with alg %q", alg)
+		}
+		secret, err := key.SymmetricKey()
+		if err != nil {
+			return nil, err
+		}
+		return hmacSigningMethod(alg, secret), nil
+	}
+	if !verifyOnly {
+		return nil, errors.New("only oct JWK can be used for signing")
+	}
+
+	public, err := key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return signingmethods.NewVerifier(alg, public)
+}
+
+func rsaSigningMethod(alg string, key []byte, verifyOnly bool) (jwt.SigningMethod, error) {
+	private, err := signingmethods.ParseRSAPrivateKeyFromPEM(key)
+	if err == nil {
+		switch alg {
+		case "RS256":
+			return signingmethods.NewRS256(private), nil
+		case "RS384":
+			return signingmethods.NewRS384(private), nil
+		default:
+			return signingmethods.NewRS512(private), nil
+		}
+	}
+	if !verifyOnly {
+		return nil, err
+	}
+
+	public, err := signingmethods.ParseRSAPublicKeyFromPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	switch alg {
+	case "RS256":
+		return signingmethods.NewRS256Verifier(public), nil
+	case "RS384":
+		return signingmethods.NewRS384Verifier(public), nil
+	default:
+		return signingmethods.NewRS512Verifier(public), nil
+	}
+}
+
+func ecSigningMethod(alg string, key []byte, verifyOnly bool) (jwt.SigningMethod, error) {
+	private, err := signingmethods.ParseECPrivateKeyFromPEM(key)
+	if err == nil {
+		switch alg {
+		case "ES256":
+			return signingmethods.NewES256(private), nil
+		case "ES384":
+			return signingmethods.NewES384(private), nil
+		default:
+			return signingmethods.NewES512(private), nil
+		}
+	}
+	if !verifyOnly {
+		return nil, err
+	}
+
+	public, err := signingmethods.ParseECPublicKeyFromPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	switch alg {
+	case "ES256":
+		return signingmethods.NewES256Verifier(public), nil
+	case "ES384":
+		return signingmethods.NewES384Verifier(public), nil
+	default:
+		return signingmethods.NewES512Verifier(public), nil
+	}
+}
@@ -0,0 +1,102 @@
+// Command jwt decodes, verifies and signs JWT tokens offline.
+//
+// Usage:
+//
+//	jwt decode [token]
+//	jwt verify -alg RS256 -key public.pem [token]
+//	jwt sign -alg HS256 -secret secret [-claims json] [-claim name=value]...
+//	jwt keygen -type rsa|ec|ed25519|hmac [-kid kid] [-format pem|jwk] [-out dir]
+//	jwt jwks [-kid file|thumbprint] dir
+//
+// Token is read from stdin when it is not passed as argument.
+// -key is PEM, JWK or HMAC key file of keygen, both formats of keygen HMAC key are the same secret.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var errUsage = errors.New("usage: jwt <decode|verify|sign|keygen|jwks> [flags] [args]")
+
+// errFlags is returned when flag set has already written parse error and usage to stderr.
+var errFlags = errors.New("invalid flags")
+
+// now is replaced in tests.
+var now = time.Now
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"decode": runDecode,
+	"verify": runVerify,
+	"sign":   runSign,
+	"keygen": runKeygen,
+	"jwks":   runJWKS,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, errUsage)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, errUsage)
+		return 2
+	}
+
+	err := cmd(args[1:], stdin, stdout, stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errFlags):
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "jwt:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// newFlagSet returns flag set of command that writes usage to stderr on -h and parse error.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("jwt "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// parseFlags parses args, parse error is replaced by errFlags because flag set has already reported it.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return errFlags
+	}
+	return err
+}
+
+func readToken(args []string, stdin io.Reader) (string, error) {
+	switch len(args) {
+	case 0:
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed read token, %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case 1:
+		return strings.TrimSpace(args[0]), nil
+	default:
+		return "", errUsage
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+var errBadClaim = errors.New("claim must be name=value")
+
+type claimFlags jwt.Payload
+
+func (c claimFlags) String() string {
+	return ""
+}
+
+// Set adds claim, value is parsed as JSON and used as string if it is not valid JSON.
+func (c claimFlags) Set(value string) error {
+	name, raw, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return errBadClaim
+	}
+
+	var claim any
+	if json.Unmarshal([]byte(raw), &claim) != nil {
+		claim = raw
+	}
+	c[name] = claim
+
+	return nil
+}
+
+func runSign(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var keys keyFlags
+	claims := claimFlags{}
+	flags := newFlagSet("sign", stderr)
+	flags.StringVar(&keys.alg, "alg", "HS256", "signing algorithm, e.g. HS256, RS256, ES256")
+	flags.StringVar(&keys.keyFile, "key", "", "PEM private key file or HMAC secret file")
+	flags.StringVar(&keys.secret, "secret", "", "HMAC secret")
+	claimsJSON := flags.String("claims", "", "claims JSON object, read from stdin when empty and no -claim is set")
+	flags.Var(claims, "claim", "claim name=value, could be repeated")
+	ttl := flags.Duration("ttl", 0, "set iat and exp claims")
+	issuer := flags.String("iss", "", "set iss claim")
+	audience := flags.String("aud", "", "set aud claim")
+	tokenID := flags.Bool("jti", false, "set random jti claim")
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errUsage
+	}
+
+	signingMethod, err := keys.signingMethod(false)
+	if err != nil {
+		return err
+	}
+
+	payload := jwt.Payload{}
+	switch {
+	case *claimsJSON != "":
+		err = json.Unmarshal([]byte(*claimsJSON), &payload)
+	case len(claims) == 0:
+		err = json.NewDecoder(stdin).Decode(&payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed read claims, %w", err)
+	}
+	for name, value := range claims {
+		payload[name] = value
+	}
+
+	opts := []jwt.CreatorOption{jwt.WithClock(jwt.ClockFunc(now))}
+	if *ttl > 0 {
+		opts = append(opts, jwt.WithTTL(*ttl))
+	}
+	if *issuer != "" {
+		opts = append(opts, jwt.WithIssuer(*issuer))
+	}
+	if *audience != "" {
+		opts = append(opts, jwt.WithAudience(*audience))
+	}
+	if *tokenID {
+		opts = append(opts, jwt.WithTokenID())
+	}
+
+	token, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, opts...).CreateToken(payload)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, token)
+
+	return nil
+}
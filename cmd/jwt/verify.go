@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amidgo/jwt"
+)
+
+type invalidTokenError struct {
+	err error
+}
+
+func (e invalidTokenError) Error() string {
+	var tokenInvalidErr jwt.TokenInvalidError
+	if errors.As(e.err, &tokenInvalidErr) && tokenInvalidErr.Error() != e.err.Error() {
+		return fmt.Sprintf("invalid token: %s (%s)", tokenInvalidErr.Code(), e.err)
+	}
+	return "invalid token: " + e.err.Error()
+}
+
+func (e invalidTokenError) Unwrap() error {
+	return e.err
+}
+
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var keys keyFlags
+	flags := newFlagSet("verify", stderr)
+	flags.StringVar(&keys.alg, "alg", "", "signing algorithm, e.g. HS256, RS256, ES256")
+	flags.StringVar(&keys.keyFile, "key", "", "PEM key file or HMAC secret file")
+	flags.StringVar(&keys.secret, "secret", "", "HMAC secret")
+	checkExp := flags.Bool("exp", true, "require not expired exp claim")
+	err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	signingMethod, err := keys.signingMethod(true)
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := readToken(flags.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	token, err := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod).ParseToken(accessToken)
+	if err != nil {
+		return invalidTokenError{err: err}
+	}
+	if *checkExp {
+		err = jwt.ValidateToken(token, jwt.VerifyTokenExpiration)
+		if err != nil {
+			return invalidTokenError{err: err}
+		}
+	}
+
+	fmt.Fprintln(stdout, "valid")
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+)
+
+//go:generate mockgen -source context.go -destination mocks/context_mocks.go -package jwtmocks
+
+// ErrSigningMethodUnavailable must be wrapped by signing method errors other than signature mismatch, e.g. KMS transport error.
+// Parser reports such errors and context errors without ErrSignNotVerified, so they are not taken for invalid signature.
+var ErrSigningMethodUnavailable = errors.New("signing method unavailable")
+
+// ContextSigningMethod is SigningMethod that accepts context, e.g. remote KMS signer.
+// JwtTokenCreator and JwtTokenParser pass context to signing method that implements it.
+type ContextSigningMethod interface {
+	Alg() string
+	SignContext(ctx context.Context, stringToSign string) ([]byte, error)
+	VerifyContext(ctx context.Context, verifyData string, sign []byte) error
+}
+
+type ContextTokenParser interface {
+	ParseTokenContext(ctx context.Context, accessToken string) (Token, error)
+}
+
+type ContextTokenCreator interface {
+	CreateTokenContext(ctx context.Context, payload Payload) (string, error)
+}
+
+type ContextTokenValidator interface {
+	ValidateTokenContext(ctx context.Context, token Token) error
+}
+
+type ContextTokenValidatorFunc func(ctx context.Context, t Token) error
+
+func (f ContextTokenValidatorFunc) ValidateTokenContext(ctx context.Context, token Token) error {
+	return f(ctx, token)
+}
+
+// NewContextSigningMethod adapts signingMethod to ContextSigningMethod,
+// context is checked before the call since SigningMethod can't be cancelled.
+func NewContextSigningMethod(signingMethod SigningMethod) ContextSigningMethod {
+	if contextSigningMethod, ok := signingMethod.(ContextSigningMethod); ok {
+		return contextSigningMethod
+	}
+	return contextSigningMethod{signingMethod: signingMethod}
+}
+
+// NewBackgroundSigningMethod adapts signingMethod to SigningMethod, Sign and Verify use context.Background.
+// Returned SigningMethod implements ContextSigningMethod, so context is still passed to signingMethod by JwtTokenCreator and JwtTokenParser.
+func NewBackgroundSigningMethod(signingMethod ContextSigningMethod) SigningMethod {
+	if adapter, ok := signingMethod.(contextSigningMethod); ok {
+		return adapter.signingMethod
+	}
+	if bareSigningMethod, ok := signingMethod.(SigningMethod); ok {
+		return bareSigningMethod
+	}
+	return backgroundSigningMethod{ContextSigningMethod: signingMethod}
+}
+
+// NewContextTokenParser adapts parser to ContextTokenParser, context is checked before the call.
+func NewContextTokenParser(parser TokenParser) ContextTokenParser {
+	if contextParser, ok := parser.(ContextTokenParser); ok {
+		return contextParser
+	}
+	return contextTokenParser{parser: parser}
+}
+
+// NewBackgroundTokenParser adapts parser to TokenParser, ParseToken uses context.Background.
+func NewBackgroundTokenParser(parser ContextTokenParser) TokenParser {
+	if adapter, ok := parser.(contextTokenParser); ok {
+		return adapter.parser
+	}
+	if bareParser, ok := parser.(TokenParser); ok {
+		return bareParser
+	}
+	return backgroundTokenParser{ContextTokenParser: parser}
+}
+
+// NewContextTokenCreator adapts creator to ContextTokenCreator, context is checked before the call.
+func NewContextTokenCreator(creator TokenCreator) ContextTokenCreator {
+	if contextCreator, ok := creator.(ContextTokenCreator); ok {
+		return contextCreator
+	}
+	return contextTokenCreator{creator: creator}
+}
+
+// NewBackgroundTokenCreator adapts creator to TokenCreator, CreateToken uses context.Background.
+func NewBackgroundTokenCreator(creator ContextTokenCreator) TokenCreator {
+	if adapter, ok := creator.(contextTokenCreator); ok {
+		return adapter.creator
+	}
+	if bareCreator, ok := creator.(TokenCreator); ok {
+		return bareCreator
+	}
+	return backgroundTokenCreator{ContextTokenCreator: creator}
+}
+
+// NewContextTokenValidator adapts validator to ContextTokenValidator, context is checked before the call.
+func NewContextTokenValidator(validator TokenValidator) ContextTokenValidator {
+	if contextValidator, ok := validator.(ContextTokenValidator); ok {
+		return contextValidator
+	}
+	return contextTokenValidator{validator: validator}
+}
+
+// NewBackgroundTokenValidator adapts validator to TokenValidator, ValidateToken uses context.Background.
+// Returned TokenValidator implements ContextTokenValidator, so ValidateTokenContext passes context to validator.
+func NewBackgroundTokenValidator(validator ContextTokenValidator) TokenValidator {
+	if adapter, ok := validator.(contextTokenValidator); ok {
+		return adapter.validator
+	}
+	if bareValidator, ok := validator.(TokenValidator); ok {
+		return bareValidator
+	}
+	return backgroundTokenValidator{ContextTokenValidator: validator}
+}
+
+// ValidateTokenContext validates token, context is passed to validators that implement ContextTokenValidator.
+func ValidateTokenContext(ctx context.Context, t Token, tokenValidators ...TokenValidator) error {
+	for _, tokenValidator := range tokenValidators {
+		err := NewContextTokenValidator(tokenValidator).ValidateTokenContext(ctx, t)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseAndValidateTokenContext is ParseAndValidateToken that passes context to parser and validators.
+func ParseAndValidateTokenContext(ctx context.Context, parser ContextTokenParser, accessToken string, tokenValidators ...TokenValidator) (Token, error) {
+	token, err := parser.ParseTokenContext(ctx, accessToken)
+	if err != nil {
+		return token, err
+	}
+	err = ValidateTokenContext(ctx, token, tokenValidators...)
+	if err != nil {
+		return token, &ParseError{Stage: StageValidation, Segment: NoSegment, Alg: token.Header.Alg, Err: err}
+	}
+	return token, nil
+}
+
+// signContext and verifyContext don't use adapter to keep hot path free of allocations.
+func signContext(ctx context.Context, signingMethod SigningMethod, stringToSign string) ([]byte, error) {
+	if contextSigningMethod, ok := signingMethod.(ContextSigningMethod); ok {
+		return contextSigningMethod.SignContext(ctx, stringToSign)
+	}
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	return signingMethod.Sign(stringToSign)
+}
+
+func verifyContext(ctx context.Context, signingMethod SigningMethod, verifyData string, sign []byte) error {
+	if contextSigningMethod, ok := signingMethod.(ContextSigningMethod); ok {
+		return contextSigningMethod.VerifyContext(ctx, verifyData, sign)
+	}
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+	return signingMethod.Verify(verifyData, sign)
+}
+
+type contextSigningMethod struct {
+	signingMethod SigningMethod
+}
+
+func (s contextSigningMethod) Alg() string {
+	return s.signingMethod.Alg()
+}
+
+func (s contextSigningMethod) SignContext(ctx context.Context, stringToSign string) ([]byte, error) {
+	return signContext(ctx, s.signingMethod, stringToSign)
+}
+
+func (s contextSigningMethod) VerifyContext(ctx context.Context, verifyData string, sign []byte) error {
+	return verifyContext(ctx, s.signingMethod, verifyData, sign)
+}
+
+type backgroundSigningMethod struct {
+	ContextSigningMethod
+}
+
+func (s backgroundSigningMethod) Sign(stringToSign string) ([]byte, error) {
+	return s.SignContext(context.Background(), stringToSign)
+}
+
+func (s backgroundSigningMethod) Verify(verifyData string, sign []byte) error {
+	return s.VerifyContext(context.Background(), verifyData, sign)
+}
+
+type contextTokenParser struct {
+	parser TokenParser
+}
+
+func (p contextTokenParser) ParseTokenContext(ctx context.Context, accessToken string) (Token, error) {
+	err := ctx.Err()
+	if err != nil {
+		return Token{}, err
+	}
+	return p.parser.ParseToken(accessToken)
+}
+
+type backgroundTokenParser struct {
+	ContextTokenParser
+}
+
+func (p backgroundTokenParser) ParseToken(accessToken string) (Token, error) {
+	return p.ParseTokenContext(context.Background(), accessToken)
+}
+
+type contextTokenCreator struct {
+	creator TokenCreator
+}
+
+func (c contextTokenCreator) CreateTokenContext(ctx context.Context, payload Payload) (string, error) {
+	err := ctx.Err()
+	if err != nil {
+		return "", err
+	}
+	return c.creator.CreateToken(payload)
+}
+
+type backgroundTokenCreator struct {
+	ContextTokenCreator
+}
+
+func (c backgroundTokenCreator) CreateToken(payload Payload) (string, error) {
+	return c.CreateTokenContext(context.Background(), payload)
+}
+
+type contextTokenValidator struct {
+	validator TokenValidator
+}
+
+func (v contextTokenValidator) ValidateTokenContext(ctx context.Context, token Token) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+	return v.validator.ValidateToken(token)
+}
+
+type backgroundTokenValidator struct {
+	ContextTokenValidator
+}
+
+func (v backgroundTokenValidator) ValidateToken(token Token) error {
+	return v.ValidateTokenContext(context.Background(), token)
+}
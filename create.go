@@ -1,8 +1,10 @@
 package jwt
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 //go:generate mockgen -source create.go -destination mocks/create_mocks.go -package jwtmocks
@@ -14,10 +16,82 @@ type Encoder interface {
 type JwtTokenCreator struct {
 	encoder       Encoder
 	signingMethod SigningMethod
+	claims        registeredClaims
+	headerType    string
+	keyID         string
 }
 
-func NewTokenCreator(encoder Encoder, signingMethod SigningMethod) *JwtTokenCreator {
-	return &JwtTokenCreator{encoder: encoder, signingMethod: signingMethod}
+func NewTokenCreator(encoder Encoder, signingMethod SigningMethod, opts ...CreatorOption) *JwtTokenCreator {
+	creator := &JwtTokenCreator{
+		encoder:       encoder,
+		signingMethod: signingMethod,
+		claims:        registeredClaims{clock: SystemClock},
+	}
+	for _, opt := range opts {
+		opt(creator)
+	}
+	return creator
+}
+
+// CreatorOption configures token header and registered claims that JwtTokenCreator sets when they are absent from the payload.
+// Random jti is set whenever any registered claims option is used.
+type CreatorOption func(c *JwtTokenCreator)
+
+// WithTTL sets iat and exp claims, exp is now plus ttl.
+func WithTTL(ttl time.Duration) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.ttl = ttl
+	}
+}
+
+// WithNotBefore sets iat and nbf claims, nbf is now plus offset.
+func WithNotBefore(offset time.Duration) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.notBefore = &offset
+	}
+}
+
+// WithIssuer sets iss claim.
+func WithIssuer(issuer string) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.issuer = issuer
+	}
+}
+
+// WithAudience sets aud claim, single audience is set as string.
+func WithAudience(audience ...string) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.audience = audience
+	}
+}
+
+// WithTokenID sets jti claim to a random id, every other registered claims option sets it too,
+// so WithTokenID is needed only when it is the single option.
+func WithTokenID() CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.tokenID = true
+	}
+}
+
+// WithClock sets clock used for iat, exp and nbf claims.
+func WithClock(clock Clock) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.claims.clock = clock
+	}
+}
+
+// WithHeaderType sets typ header, JWT is used by default, e.g. at+jwt for access tokens of RFC 9068.
+func WithHeaderType(typ string) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.headerType = typ
+	}
+}
+
+// WithKeyID sets kid header, so parser can select verification key from JWK Set.
+func WithKeyID(kid string) CreatorOption {
+	return func(c *JwtTokenCreator) {
+		c.keyID = kid
+	}
 }
 
 func MakeJwtHeader(alg string) string {
@@ -25,17 +99,30 @@ func MakeJwtHeader(alg string) string {
 }
 
 func (c *JwtTokenCreator) CreateToken(payload Payload) (string, error) {
+	return c.CreateTokenContext(context.Background(), payload)
+}
+
+// CreateTokenContext creates token, ctx is passed to signing method that implements ContextSigningMethod.
+func (c *JwtTokenCreator) CreateTokenContext(ctx context.Context, payload Payload) (string, error) {
+	payload, err := c.claims.fill(payload)
+	if err != nil {
+		return "", err
+	}
+
 	rawPayload, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("failed marshal payload, %w", err)
 	}
 
-	headerString := MakeJwtHeader(c.signingMethod.Alg())
+	headerString, err := c.header()
+	if err != nil {
+		return "", err
+	}
 	encodedHeader := c.encoder.EncodeToString([]byte(headerString))
 	encodedPayload := c.encoder.EncodeToString(rawPayload)
 	signingString := encodedHeader + "." + encodedPayload
 
-	sign, err := c.signingMethod.Sign(signingString)
+	sign, err := signContext(ctx, c.signingMethod, signingString)
 	if err != nil {
 		return "", err
 	}
@@ -44,3 +131,18 @@ func (c *JwtTokenCreator) CreateToken(payload Payload) (string, error) {
 
 	return signingString + "." + encodedSign, nil
 }
+
+func (c *JwtTokenCreator) header() (string, error) {
+	if c.headerType == "" && c.keyID == "" {
+		return MakeJwtHeader(c.signingMethod.Alg()), nil
+	}
+	typ := c.headerType
+	if typ == "" {
+		typ = "JWT"
+	}
+	header, err := json.Marshal(Header{Type: typ, Alg: c.signingMethod.Alg(), KeyID: c.keyID})
+	if err != nil {
+		return "", fmt.Errorf("failed marshal header, %w", err)
+	}
+	return string(header), nil
+}
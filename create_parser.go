@@ -20,12 +20,12 @@ type EncodeDecoder interface {
 	Encoder
 }
 
-func NewTokenCreateParser(encDec EncodeDecoder, signingMethod SigningMethod) TokenCreateParser {
+func NewTokenCreateParser(encDec EncodeDecoder, signingMethod SigningMethod, opts ...CreatorOption) TokenCreateParser {
 	return struct {
 		TokenParser
 		TokenCreator
 	}{
 		TokenParser:  NewTokenParser(encDec, signingMethod),
-		TokenCreator: NewTokenCreator(encDec, signingMethod),
+		TokenCreator: NewTokenCreator(encDec, signingMethod, opts...),
 	}
 }
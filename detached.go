@@ -0,0 +1,185 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+var ErrUnsupportedCrit TokenInvalidError = "unsupported_crit"
+
+// contentHeader is JOSE header of token with arbitrary content, b64 is defined by RFC 7797.
+type contentHeader struct {
+	Header
+	B64  *bool    `json:"b64,omitempty"`
+	Crit []string `json:"crit,omitempty"`
+}
+
+func (h contentHeader) encoded() bool {
+	return h.B64 == nil || *h.B64
+}
+
+func makeContentHeader(alg string, unencoded bool) string {
+	if unencoded {
+		return fmt.Sprintf(`{"alg":"%s","b64":false,"crit":["b64"]}`, alg)
+	}
+	return fmt.Sprintf(`{"alg":"%s"}`, alg)
+}
+
+// ContentCreator signs arbitrary byte content instead of JSON payload.
+type ContentCreator struct {
+	encoder       Encoder
+	signingMethod SigningMethod
+}
+
+func NewContentCreator(encoder Encoder, signingMethod SigningMethod) *ContentCreator {
+	return &ContentCreator{encoder: encoder, signingMethod: signingMethod}
+}
+
+// CreateToken returns compact token with base64url encoded content.
+func (c *ContentCreator) CreateToken(content []byte) (string, error) {
+	encodedHeader := c.encoder.EncodeToString([]byte(makeContentHeader(c.signingMethod.Alg(), false)))
+	signingString := encodedHeader + "." + c.encoder.EncodeToString(content)
+
+	sign, err := c.signingMethod.Sign(signingString)
+	if err != nil {
+		return "", err
+	}
+
+	return signingString + "." + c.encoder.EncodeToString(sign), nil
+}
+
+// CreateDetached returns compact token with empty payload segment, see RFC 7515 appendix F.
+func (c *ContentCreator) CreateDetached(content []byte) (string, error) {
+	return c.createDetached(content, false)
+}
+
+// CreateUnencodedDetached returns detached token signed over content as is, header has b64 false, see RFC 7797.
+func (c *ContentCreator) CreateUnencodedDetached(content []byte) (string, error) {
+	return c.createDetached(content, true)
+}
+
+func (c *ContentCreator) createDetached(content []byte, unencoded bool) (string, error) {
+	encodedHeader := c.encoder.EncodeToString([]byte(makeContentHeader(c.signingMethod.Alg(), unencoded)))
+
+	var signingString string
+	if unencoded {
+		signingString = encodedHeader + "." + string(content)
+	} else {
+		signingString = encodedHeader + "." + c.encoder.EncodeToString(content)
+	}
+
+	sign, err := c.signingMethod.Sign(signingString)
+	if err != nil {
+		return "", err
+	}
+
+	return encodedHeader + ".." + c.encoder.EncodeToString(sign), nil
+}
+
+// ContentParser verifies tokens created by ContentCreator.
+// Encoder is required to encode detached content.
+type ContentParser struct {
+	encDec        EncodeDecoder
+	signingMethod SigningMethod
+}
+
+func NewContentParser(encDec EncodeDecoder, signingMethod SigningMethod) *ContentParser {
+	return &ContentParser{encDec: encDec, signingMethod: signingMethod}
+}
+
+// ParseContent verifies token with attached base64url encoded content and returns the content.
+func (p *ContentParser) ParseContent(token string) (Header, []byte, error) {
+	rawToken, err := ParseRawToken(token)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	header, err := p.decodeHeader(rawToken)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if !header.encoded() {
+		return Header{}, nil, &ParseError{Stage: StageDecodePayload, Segment: PayloadSegment, Alg: header.Alg, Err: ErrBadToken}
+	}
+
+	content, err := p.encDec.DecodeString(rawToken.Payload())
+	if err != nil {
+		return Header{}, nil, decodePayloadError(header.Header, ErrBadToken, err)
+	}
+
+	err = p.verifySign(rawToken, rawToken.Header()+"."+rawToken.Payload())
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	return header.Header, content, nil
+}
+
+// VerifyDetached verifies detached token against content, b64 false header is supported.
+func (p *ContentParser) VerifyDetached(token string, content []byte) (Header, error) {
+	rawToken, err := ParseRawToken(token)
+	if err != nil {
+		return Header{}, err
+	}
+	if rawToken.Payload() != "" {
+		return Header{}, &ParseError{Stage: StageSplit, Segment: PayloadSegment, Err: ErrBadToken}
+	}
+	header, err := p.decodeHeader(rawToken)
+	if err != nil {
+		return Header{}, err
+	}
+
+	var signingString string
+	if header.encoded() {
+		signingString = rawToken.Header() + "." + p.encDec.EncodeToString(content)
+	} else {
+		signingString = rawToken.Header() + "." + string(content)
+	}
+
+	err = p.verifySign(rawToken, signingString)
+	if err != nil {
+		return Header{}, err
+	}
+
+	return header.Header, nil
+}
+
+func (p *ContentParser) decodeHeader(rawToken RawToken) (header contentHeader, err error) {
+	rawHeader, err := p.encDec.DecodeString(rawToken.Header())
+	if err != nil {
+		return header, decodeHeaderError(ErrBadToken, err)
+	}
+	err = json.Unmarshal(rawHeader, &header)
+	if err != nil {
+		return header, decodeHeaderError(ErrUnmarshalToken, err)
+	}
+	if p.signingMethod.Alg() != header.Alg {
+		return header, &ParseError{Stage: StageAlgCheck, Segment: HeaderSegment, Alg: header.Alg, Err: ErrWrongAlgoritm}
+	}
+
+	// b64 must be understood and listed in crit, see RFC 7797 section 6
+	b64Critical := false
+	for _, param := range header.Crit {
+		if param != "b64" {
+			return header, &ParseError{Stage: StageDecodeHeader, Segment: HeaderSegment, Alg: header.Alg, Err: ErrUnsupportedCrit}
+		}
+		b64Critical = true
+	}
+	if header.B64 != nil && !b64Critical {
+		return header, &ParseError{Stage: StageDecodeHeader, Segment: HeaderSegment, Alg: header.Alg, Err: ErrUnsupportedCrit}
+	}
+
+	return header, nil
+}
+
+func (p *ContentParser) verifySign(rawToken RawToken, signingString string) error {
+	sign, err := p.encDec.DecodeString(rawToken.Sign())
+	if err != nil {
+		return signatureError(p.signingMethod.Alg(), ErrBadToken, err)
+	}
+	err = p.signingMethod.Verify(signingString, sign)
+	if err != nil {
+		return verifyError(context.Background(), p.signingMethod.Alg(), err)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package dpop_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/dpop"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+const resourceURL = "https://resource.example.org/protected"
+
+func newES256ProofCreator(t *testing.T, opts ...dpop.ProofCreatorOption) (*dpop.ProofCreator, *ecdsa.PrivateKey) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	creator, err := dpop.NewProofCreator(signingmethods.NewES256(private), &private.PublicKey, opts...)
+	assert.NilError(t, err)
+
+	return creator, private
+}
+
+func Test_ProofCreator(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	creator, private := newES256ProofCreator(t, dpop.WithClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	proof, err := creator.CreateProof(http.MethodPost, resourceURL, dpop.WithAccessToken("access"), dpop.WithNonce("server-nonce"))
+	assert.NilError(t, err)
+
+	rawToken, err := jwt.ParseRawToken(proof)
+	assert.NilError(t, err)
+	rawHeader, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	assert.NilError(t, err)
+
+	var header struct {
+		Type string  `json:"typ"`
+		Alg  string  `json:"alg"`
+		JWK  jwk.Key `json:"jwk"`
+	}
+	assert.NilError(t, json.Unmarshal(rawHeader, &header))
+	assert.Equal(t, header.Type, "dpop+jwt")
+	assert.Equal(t, header.Alg, "ES256")
+
+	expectedKey, err := jwk.NewECPublicKey(&private.PublicKey)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, header.JWK, expectedKey)
+
+	thumbprint, err := dpop.Thumbprint(expectedKey)
+	assert.NilError(t, err)
+	assert.Equal(t, creator.Thumbprint(), thumbprint)
+
+	token, err := jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewES256Verifier(&private.PublicKey)).ParseToken(proof)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Payload["htm"], http.MethodPost)
+	assert.Equal(t, token.Payload["htu"], resourceURL)
+	assert.Equal(t, token.Payload["iat"], float64(now.Unix()))
+	assert.Equal(t, token.Payload["ath"], dpop.AccessTokenHash("access"))
+	assert.Equal(t, token.Payload["nonce"], "server-nonce")
+	assert.Assert(t, token.Payload["jti"] != "")
+}
+
+func Test_AccessTokenHash(t *testing.T) {
+	// RFC 9449 section 7.1
+	assert.Equal(t, dpop.AccessTokenHash("Kz~8mXK1EalYznwH-LC-1fBAo.4Ljp~zsPE_NeO.gxU"), "fUHyO2r2Z3DZ53EsNrWBb0xWXoaNy59IiKCAqksmQEo")
+}
+
+func Test_Validator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	clock := jwt.ClockFunc(func() time.Time { return now })
+
+	creator, _ := newES256ProofCreator(t, dpop.WithClock(clock))
+	otherCreator, _ := newES256ProofCreator(t, dpop.WithClock(clock))
+	staleCreator, _ := newES256ProofCreator(t, dpop.WithClock(jwt.ClockFunc(func() time.Time { return now.Add(-2 * time.Minute) })))
+	futureCreator, _ := newES256ProofCreator(t, dpop.WithClock(jwt.ClockFunc(func() time.Time { return now.Add(time.Minute) })))
+
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(mustReadFile(t, "../signingmethods/testdata/sample_key"))
+	assert.NilError(t, err)
+	rsaCreator, err := dpop.NewProofCreator(signingmethods.NewRS256(rsaKey), &rsaKey.PublicKey, dpop.WithClock(clock))
+	assert.NilError(t, err)
+
+	accessToken := "access"
+	boundToken := jwt.Token{Payload: jwt.Payload{"sub": "user", "cnf": map[string]any{"jkt": creator.Thumbprint()}}}
+
+	mustProof := func(creator *dpop.ProofCreator, method, url string, opts ...dpop.ProofOption) string {
+		proof, err := creator.CreateProof(method, url, opts...)
+		assert.NilError(t, err)
+		return proof
+	}
+
+	validProof := mustProof(creator, http.MethodGet, resourceURL, dpop.WithAccessToken(accessToken))
+	segments := strings.Split(validProof, ".")
+
+	privateKeyProof := func() string {
+		private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NilError(t, err)
+		key, err := jwk.NewECPrivateKey(private)
+		assert.NilError(t, err)
+		header, err := json.Marshal(map[string]any{"typ": "dpop+jwt", "alg": "ES256", "jwk": key})
+		assert.NilError(t, err)
+		return base64.RawURLEncoding.EncodeToString(header) + "." + segments[1] + "." + segments[2]
+	}()
+
+	jwtTypeProof, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingmethods.NewHS256("secret")).CreateToken(jwt.Payload{"htm": "GET"})
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name        string
+		validator   *dpop.Validator
+		proof       string
+		method, url string
+		bound       bool
+		token       jwt.Token
+		expectedErr error
+	}{
+		{
+			name:  "valid",
+			proof: mustProof(creator, http.MethodGet, resourceURL),
+		},
+		{
+			name:  "valid bound",
+			proof: validProof,
+			bound: true,
+			token: boundToken,
+		},
+		{
+			name:  "valid RS256",
+			proof: mustProof(rsaCreator, http.MethodGet, resourceURL),
+		},
+		{
+			name:  "htu query and fragment ignored, host case and default port",
+			proof: mustProof(creator, http.MethodGet, "HTTPS://Resource.Example.org:443/protected"),
+			url:   resourceURL + "?page=2#top",
+		},
+		{
+			name:        "replayed",
+			proof:       validProof,
+			bound:       true,
+			token:       boundToken,
+			expectedErr: dpop.ErrProofReplayed,
+		},
+		{
+			name:        "method mismatch",
+			proof:       mustProof(creator, http.MethodPost, resourceURL),
+			expectedErr: dpop.ErrMethodMismatch,
+		},
+		{
+			name:        "url mismatch",
+			proof:       mustProof(creator, http.MethodGet, "https://resource.example.org/other"),
+			expectedErr: dpop.ErrURLMismatch,
+		},
+		{
+			name:        "stale",
+			proof:       mustProof(staleCreator, http.MethodGet, resourceURL),
+			expectedErr: dpop.ErrProofExpired,
+		},
+		{
+			name:        "issued in future",
+			proof:       mustProof(futureCreator, http.MethodGet, resourceURL),
+			expectedErr: dpop.ErrProofExpired,
+		},
+		{
+			name:        "no ath",
+			proof:       mustProof(creator, http.MethodGet, resourceURL),
+			bound:       true,
+			token:       boundToken,
+			expectedErr: dpop.ErrAccessTokenHashMismatch,
+		},
+		{
+			name:        "ath of other token",
+			proof:       mustProof(creator, http.MethodGet, resourceURL, dpop.WithAccessToken("other")),
+			bound:       true,
+			token:       boundToken,
+			expectedErr: dpop.ErrAccessTokenHashMismatch,
+		},
+		{
+			name:        "token bound to other key",
+			proof:       mustProof(otherCreator, http.MethodGet, resourceURL, dpop.WithAccessToken(accessToken)),
+			bound:       true,
+			token:       boundToken,
+			expectedErr: dpop.ErrKeyBindingMismatch,
+		},
+		{
+			name:        "token without cnf",
+			proof:       mustProof(creator, http.MethodGet, resourceURL, dpop.WithAccessToken(accessToken)),
+			bound:       true,
+			token:       jwt.Token{Payload: jwt.Payload{"sub": "user"}},
+			expectedErr: dpop.ErrKeyBindingMismatch,
+		},
+		{
+			name:        "alg not accepted",
+			validator:   dpop.NewValidator(dpop.WithAlgorithms("ES256"), dpop.WithValidatorClock(clock)),
+			proof:       mustProof(rsaCreator, http.MethodGet, resourceURL),
+			expectedErr: jwt.ErrWrongAlgoritm,
+		},
+		{
+			name:        "wrong sign",
+			proof:       segments[0] + "." + segments[1] + "." + strings.Split(mustProof(creator, http.MethodGet, resourceURL), ".")[2],
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+		{
+			name:        "embedded private key",
+			proof:       privateKeyProof,
+			expectedErr: dpop.ErrInvalidProof,
+		},
+		{
+			name:        "not dpop+jwt typ",
+			proof:       jwtTypeProof,
+			expectedErr: dpop.ErrInvalidProof,
+		},
+		{
+			name:        "not jwt",
+			proof:       "proof",
+			expectedErr: jwt.ErrBadToken,
+		},
+	}
+
+	validator := dpop.NewValidator(dpop.WithValidatorClock(clock))
+	for _, cs := range cases {
+		if cs.validator == nil {
+			cs.validator = validator
+		}
+		if cs.method == "" {
+			cs.method = http.MethodGet
+		}
+		if cs.url == "" {
+			cs.url = resourceURL
+		}
+
+		var (
+			proof dpop.Proof
+			err   error
+		)
+		if cs.bound {
+			proof, err = cs.validator.ValidateBoundProof(ctx, cs.proof, cs.method, cs.url, accessToken, cs.token)
+		} else {
+			proof, err = cs.validator.ValidateProof(ctx, cs.proof, cs.method, cs.url)
+		}
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr == nil {
+			assert.Assert(t, proof.Thumbprint != "", cs.name)
+			assert.Equal(t, proof.Token.Header.Type, dpop.ProofType, cs.name)
+		}
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	issuer := jwttest.NewIssuer(t)
+	creator, _ := newES256ProofCreator(t)
+	otherCreator, _ := newES256ProofCreator(t)
+
+	accessToken := issuer.Token(jwt.Payload{"sub": "user", "cnf": map[string]any{"jkt": creator.Thumbprint()}})
+
+	handler := dpop.Middleware(issuer.Parser(), dpop.NewValidator())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := dpop.TokenFromContext(r.Context())
+		assert.Assert(t, ok)
+		proof, ok := dpop.ProofFromContext(r.Context())
+		assert.Assert(t, ok)
+		assert.Equal(t, proof.Thumbprint, creator.Thumbprint())
+
+		w.Write([]byte(token.Payload["sub"].(string)))
+	}))
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	request := func(scheme, accessToken string, proofs ...string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/protected?query=1", nil)
+		assert.NilError(t, err)
+		req.Header.Set("Authorization", scheme+" "+accessToken)
+		for _, proof := range proofs {
+			req.Header.Add("DPoP", proof)
+		}
+		resp, err := server.Client().Do(req)
+		assert.NilError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	mustProof := func(creator *dpop.ProofCreator) string {
+		proof, err := creator.CreateProof(http.MethodGet, server.URL+"/protected", dpop.WithAccessToken(accessToken))
+		assert.NilError(t, err)
+		return proof
+	}
+
+	resp := request("DPoP", accessToken, mustProof(creator))
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+
+	cases := []struct {
+		name          string
+		resp          *http.Response
+		expectedError string
+	}{
+		{
+			name:          "bearer scheme",
+			resp:          request("Bearer", accessToken, mustProof(creator)),
+			expectedError: "invalid_request",
+		},
+		{
+			name:          "no proof",
+			resp:          request("DPoP", accessToken),
+			expectedError: "invalid_dpop_proof",
+		},
+		{
+			name:          "two proofs",
+			resp:          request("DPoP", accessToken, mustProof(creator), mustProof(creator)),
+			expectedError: "invalid_dpop_proof",
+		},
+		{
+			name:          "invalid access token",
+			resp:          request("DPoP", issuer.ExpiredToken(jwt.Payload{"sub": "user"})+"x", mustProof(creator)),
+			expectedError: "invalid_token",
+		},
+		{
+			name:          "proof of other key",
+			resp:          request("DPoP", accessToken, mustProof(otherCreator)),
+			expectedError: "invalid_token",
+		},
+	}
+
+	for _, cs := range cases {
+		assert.Equal(t, cs.resp.StatusCode, http.StatusUnauthorized, cs.name)
+		authenticate := cs.resp.Header.Get("WWW-Authenticate")
+		assert.Assert(t, strings.HasPrefix(authenticate, `DPoP error="`+cs.expectedError+`"`), cs.name+": "+authenticate)
+		assert.Assert(t, strings.Contains(authenticate, `algs="ES256 ES384 ES512 RS256 RS384 RS512"`), cs.name)
+	}
+}
+
+func mustReadFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(name)
+	assert.NilError(t, err)
+	return data
+}
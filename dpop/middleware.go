@@ -0,0 +1,115 @@
+package dpop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+type contextKey struct{}
+
+type requestAuth struct {
+	token jwt.Token
+	proof Proof
+}
+
+// TokenFromContext returns access token verified by Middleware.
+func TokenFromContext(ctx context.Context) (jwt.Token, bool) {
+	auth, ok := ctx.Value(contextKey{}).(requestAuth)
+	return auth.token, ok
+}
+
+// ProofFromContext returns DPoP proof verified by Middleware.
+func ProofFromContext(ctx context.Context) (Proof, bool) {
+	auth, ok := ctx.Value(contextKey{}).(requestAuth)
+	return auth.proof, ok
+}
+
+type MiddlewareOption func(m *middleware)
+
+// WithRequestURL sets function that returns url of request compared with htu claim, it is required behind reverse proxy.
+// By default url is built from request Host and path, scheme is https when request is received over TLS.
+func WithRequestURL(requestURL func(r *http.Request) string) MiddlewareOption {
+	return func(m *middleware) {
+		m.requestURL = requestURL
+	}
+}
+
+type middleware struct {
+	parser     jwt.ContextTokenParser
+	validator  *Validator
+	requestURL func(r *http.Request) string
+	next       http.Handler
+}
+
+// Middleware authenticates requests by DPoP bound access token, see RFC 9449 section 7.
+// Request must have Authorization header with DPoP scheme and single DPoP header, access token is verified by parser.
+// Verified token and proof are available by TokenFromContext and ProofFromContext,
+// failed request is rejected with 401 status and WWW-Authenticate header.
+func Middleware(parser jwt.ContextTokenParser, validator *Validator, opts ...MiddlewareOption) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		m := &middleware{
+			parser:     parser,
+			validator:  validator,
+			requestURL: RequestURL,
+			next:       next,
+		}
+		for _, opt := range opts {
+			opt(m)
+		}
+		return m
+	}
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	scheme, accessToken, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "DPoP") || accessToken == "" {
+		m.unauthorized(w, "invalid_request", "DPoP access token is required")
+		return
+	}
+	proofs := r.Header.Values("DPoP")
+	if len(proofs) != 1 {
+		m.unauthorized(w, "invalid_dpop_proof", "single DPoP proof is required")
+		return
+	}
+
+	ctx := r.Context()
+	token, err := m.parser.ParseTokenContext(ctx, accessToken)
+	if err != nil {
+		m.unauthorized(w, "invalid_token", "access token is invalid")
+		return
+	}
+
+	proof, err := m.validator.ValidateBoundProof(ctx, proofs[0], r.Method, m.requestURL(r), accessToken, token)
+	if errors.Is(err, ErrKeyBindingMismatch) {
+		m.unauthorized(w, "invalid_token", "access token is not bound to the proof key")
+		return
+	}
+	if err != nil {
+		m.unauthorized(w, "invalid_dpop_proof", "DPoP proof is invalid")
+		return
+	}
+
+	m.next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, contextKey{}, requestAuth{token: token, proof: proof})))
+}
+
+func (m *middleware) unauthorized(w http.ResponseWriter, code, description string) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+		`DPoP error=%q, error_description=%q, algs=%q`,
+		code, description, strings.Join(m.validator.Algorithms(), " "),
+	))
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+// RequestURL returns url of request without query, scheme is https when request is received over TLS.
+func RequestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.EscapedPath()
+}
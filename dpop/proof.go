@@ -0,0 +1,133 @@
+package dpop
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+)
+
+// ProofType is typ header of DPoP proof, see RFC 9449 section 4.2.
+const ProofType = "dpop+jwt"
+
+type proofHeader struct {
+	Type string  `json:"typ"`
+	Alg  string  `json:"alg"`
+	JWK  jwk.Key `json:"jwk"`
+}
+
+type ProofOption func(p jwt.Payload)
+
+// WithAccessToken binds proof to access token by ath claim, it is required when proof is sent with access token.
+func WithAccessToken(accessToken string) ProofOption {
+	return func(p jwt.Payload) {
+		p["ath"] = AccessTokenHash(accessToken)
+	}
+}
+
+// WithNonce sets nonce claim to nonce provided by server in DPoP-Nonce header.
+func WithNonce(nonce string) ProofOption {
+	return func(p jwt.Payload) {
+		p["nonce"] = nonce
+	}
+}
+
+// ProofCreator creates DPoP proofs signed by client key, public key is embedded in the proof header.
+type ProofCreator struct {
+	signingMethod jwt.SigningMethod
+	key           jwk.Key
+	thumbprint    string
+	clock         jwt.Clock
+}
+
+type ProofCreatorOption func(c *ProofCreator)
+
+// WithClock sets clock used for iat claim.
+func WithClock(clock jwt.Clock) ProofCreatorOption {
+	return func(c *ProofCreator) {
+		c.clock = clock
+	}
+}
+
+// NewProofCreator returns creator of proofs signed with signingMethod, public is public key of the signing method,
+// e.g. NewProofCreator(signingmethods.NewES256(private), &private.PublicKey).
+func NewProofCreator(signingMethod jwt.SigningMethod, public crypto.PublicKey, opts ...ProofCreatorOption) (*ProofCreator, error) {
+	key, err := jwk.NewPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint, err := Thumbprint(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ProofCreator{
+		signingMethod: signingMethod,
+		key:           key,
+		thumbprint:    thumbprint,
+		clock:         jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Thumbprint returns JWK thumbprint of the proof key, authorization server binds access token to it by cnf.jkt claim.
+func (c *ProofCreator) Thumbprint() string {
+	return c.thumbprint
+}
+
+// CreateProof returns proof of possession for HTTP request with method and url, query and fragment of url are ignored by server.
+func (c *ProofCreator) CreateProof(method, url string, opts ...ProofOption) (string, error) {
+	jti, err := jwt.NewTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	payload := jwt.Payload{
+		"jti": jti,
+		"htm": method,
+		"htu": url,
+		"iat": c.clock.Now().Unix(),
+	}
+	for _, opt := range opts {
+		opt(payload)
+	}
+
+	header, err := json.Marshal(proofHeader{Type: ProofType, Alg: c.signingMethod.Alg(), JWK: c.key})
+	if err != nil {
+		return "", fmt.Errorf("failed marshal header, %w", err)
+	}
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed marshal payload, %w", err)
+	}
+
+	signingString := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(rawPayload)
+	sign, err := c.signingMethod.Sign(signingString)
+	if err != nil {
+		return "", err
+	}
+
+	return signingString + "." + base64.RawURLEncoding.EncodeToString(sign), nil
+}
+
+// AccessTokenHash returns ath claim of access token, it is base64url SHA-256 of the token.
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Thumbprint returns base64url SHA-256 JWK thumbprint of key, it is value of cnf.jkt claim.
+func Thumbprint(key jwk.Key) (string, error) {
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(thumbprint), nil
+}
@@ -0,0 +1,273 @@
+package dpop
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var (
+	ErrInvalidProof            jwt.TokenInvalidError = "invalid_dpop_proof"
+	ErrMethodMismatch          jwt.TokenInvalidError = "dpop_method_mismatch"
+	ErrURLMismatch             jwt.TokenInvalidError = "dpop_url_mismatch"
+	ErrProofExpired            jwt.TokenInvalidError = "dpop_proof_expired"
+	ErrProofReplayed           jwt.TokenInvalidError = "dpop_proof_replayed"
+	ErrAccessTokenHashMismatch jwt.TokenInvalidError = "dpop_ath_mismatch"
+	ErrKeyBindingMismatch      jwt.TokenInvalidError = "dpop_jkt_mismatch"
+)
+
+const (
+	// DefaultProofLifetime is how long proof is accepted after its iat.
+	DefaultProofLifetime = time.Minute
+	// proofFutureLeeway accepts proofs with iat slightly in the future because of client clock skew.
+	proofFutureLeeway = 5 * time.Second
+)
+
+var defaultAlgorithms = []string{"ES256", "ES384", "ES512", "RS256", "RS384", "RS512"}
+
+// Proof is verified DPoP proof.
+type Proof struct {
+	Token jwt.Token
+	// Key is public key embedded in the proof header
+	Key jwk.Key
+	// Thumbprint is base64url SHA-256 JWK thumbprint of Key
+	Thumbprint string
+}
+
+type ValidatorOption func(v *Validator)
+
+// WithAlgorithms limits proof algorithms, ES and RS algorithms are accepted by default.
+func WithAlgorithms(algs ...string) ValidatorOption {
+	return func(v *Validator) {
+		v.algs = algs
+	}
+}
+
+// WithProofLifetime sets how long proof is accepted after its iat, DefaultProofLifetime is used by default.
+func WithProofLifetime(lifetime time.Duration) ValidatorOption {
+	return func(v *Validator) {
+		v.lifetime = lifetime
+	}
+}
+
+// WithReplayStore sets store of proof ids, jwt.MemoryReplayStore is used by default.
+// Store shared between instances is required when several servers accept proofs.
+func WithReplayStore(store jwt.ReplayStore) ValidatorOption {
+	return func(v *Validator) {
+		v.replayStore = store
+	}
+}
+
+// WithValidatorClock sets clock used to check proof iat.
+func WithValidatorClock(clock jwt.Clock) ValidatorOption {
+	return func(v *Validator) {
+		v.clock = clock
+	}
+}
+
+// Validator checks DPoP proofs on server side, see RFC 9449 section 4.3.
+type Validator struct {
+	algs        []string
+	lifetime    time.Duration
+	replayStore jwt.ReplayStore
+	clock       jwt.Clock
+}
+
+func NewValidator(opts ...ValidatorOption) *Validator {
+	v := &Validator{
+		algs:     defaultAlgorithms,
+		lifetime: DefaultProofLifetime,
+		clock:    jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.replayStore == nil {
+		v.replayStore = jwt.NewMemoryReplayStore()
+	}
+	return v
+}
+
+// Algorithms returns accepted proof algorithms, e.g. for algs parameter of WWW-Authenticate header.
+func (v *Validator) Algorithms() []string {
+	return v.algs
+}
+
+type proofRequest struct {
+	method      string
+	url         string
+	accessToken string
+	token       *jwt.Token
+}
+
+// ValidateProof validates proof sent without access token, e.g. to token endpoint.
+func (v *Validator) ValidateProof(ctx context.Context, proof, method, url string) (Proof, error) {
+	return v.validate(ctx, proof, proofRequest{method: method, url: url})
+}
+
+// ValidateBoundProof validates proof sent with access token, token is the verified access token.
+// Proof must have ath claim of accessToken and the proof key thumbprint must be equal to cnf.jkt claim of token.
+func (v *Validator) ValidateBoundProof(ctx context.Context, proof, method, url, accessToken string, token jwt.Token) (Proof, error) {
+	return v.validate(ctx, proof, proofRequest{method: method, url: url, accessToken: accessToken, token: &token})
+}
+
+func (v *Validator) validate(ctx context.Context, proof string, request proofRequest) (Proof, error) {
+	header, err := decodeHeader(proof)
+	if err != nil {
+		return Proof{}, err
+	}
+	if header.Type != ProofType || header.JWK.Kty == "" {
+		return Proof{}, ErrInvalidProof
+	}
+	if !slices.Contains(v.algs, header.Alg) {
+		return Proof{}, &jwt.ParseError{Stage: jwt.StageAlgCheck, Segment: jwt.HeaderSegment, Alg: header.Alg, Err: jwt.ErrWrongAlgoritm}
+	}
+	// key must be public, see RFC 9449 section 4.3 check 7
+	if header.JWK.Public() != header.JWK {
+		return Proof{}, ErrInvalidProof
+	}
+
+	public, err := header.JWK.PublicKey()
+	if err != nil {
+		return Proof{}, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	signingMethod, err := signingmethods.NewVerifier(header.Alg, public)
+	if err != nil {
+		return Proof{}, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+	thumbprint, err := Thumbprint(header.JWK)
+	if err != nil {
+		return Proof{}, fmt.Errorf("%w: %w", ErrInvalidProof, err)
+	}
+
+	token, err := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod).ParseTokenContext(ctx, proof)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	jti, _ := token.Payload["jti"].(string)
+	htm, _ := token.Payload["htm"].(string)
+	htu, _ := token.Payload["htu"].(string)
+	issuedAt, ok := jwt.PayloadTime(token.Payload, "iat")
+	if jti == "" || htm == "" || htu == "" || !ok {
+		return Proof{}, ErrInvalidProof
+	}
+
+	if htm != request.method {
+		return Proof{}, ErrMethodMismatch
+	}
+	if !sameURL(htu, request.url) {
+		return Proof{}, ErrURLMismatch
+	}
+
+	now := v.clock.Now()
+	if now.Sub(issuedAt) > v.lifetime || issuedAt.Sub(now) > proofFutureLeeway {
+		return Proof{}, ErrProofExpired
+	}
+
+	if request.token != nil {
+		if ath, _ := token.Payload["ath"].(string); ath != AccessTokenHash(request.accessToken) {
+			return Proof{}, ErrAccessTokenHashMismatch
+		}
+		if jkt, _ := confirmationThumbprint(*request.token); jkt != thumbprint {
+			return Proof{}, ErrKeyBindingMismatch
+		}
+	}
+
+	err = v.record(ctx, jti, issuedAt.Add(v.lifetime))
+	if err != nil {
+		return Proof{}, err
+	}
+
+	return Proof{Token: token, Key: header.JWK, Thumbprint: thumbprint}, nil
+}
+
+func (v *Validator) record(ctx context.Context, jti string, expiresAt time.Time) error {
+	firstUse, err := jwt.RecordTokenID(ctx, v.replayStore, jti, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed record proof id, %w", err)
+	}
+	if !firstUse {
+		return ErrProofReplayed
+	}
+	return nil
+}
+
+func decodeHeader(proof string) (proofHeader, error) {
+	rawToken, err := jwt.ParseRawToken(proof)
+	if err != nil {
+		return proofHeader{}, err
+	}
+
+	var header proofHeader
+	data, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	if err == nil {
+		err = json.Unmarshal(data, &header)
+	}
+	if err != nil {
+		return proofHeader{}, &jwt.ParseError{
+			Stage:   jwt.StageDecodeHeader,
+			Segment: jwt.HeaderSegment,
+			Err:     fmt.Errorf("%w: %w", jwt.ErrBadToken, err),
+		}
+	}
+	return header, nil
+}
+
+// confirmationThumbprint returns cnf.jkt claim of access token.
+func confirmationThumbprint(token jwt.Token) (string, bool) {
+	cnf, ok := token.Payload["cnf"].(map[string]any)
+	if !ok {
+		return "", false
+	}
+	jkt, ok := cnf["jkt"].(string)
+	return jkt, ok && jkt != ""
+}
+
+// sameURL compares htu with request url ignoring query and fragment, scheme and host are case insensitive
+// and default ports are omitted, see RFC 9449 section 4.3 check 9.
+func sameURL(htu, requestURL string) bool {
+	left, err := normalizeURL(htu)
+	if err != nil {
+		return false
+	}
+	right, err := normalizeURL(requestURL)
+	if err != nil {
+		return false
+	}
+	return left == right
+}
+
+func normalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", ErrURLMismatch
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if scheme == "https" && port == "443" || scheme == "http" && port == "80" {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	return scheme + "://" + host + path, nil
+}
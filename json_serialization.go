@@ -0,0 +1,261 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrSingleSignatureRequired = errors.New("flattened serialization requires exactly one signature")
+
+// ErrDuplicateHeader is returned when parameter is in both protected and unprotected header, see RFC 7515 section 7.2.1.
+var ErrDuplicateHeader TokenInvalidError = "duplicate_header"
+
+// VerificationPolicy decides whether JWS JSON token with several signatures is valid.
+type VerificationPolicy int
+
+const (
+	// VerifyAny requires at least one signature verified by parser signing methods.
+	VerifyAny VerificationPolicy = iota
+	// VerifyAll requires every signature verified by parser signing methods.
+	VerifyAll
+)
+
+// JSONSignature is signature of JWS JSON serialization, see RFC 7515 section 7.2.
+type JSONSignature struct {
+	Protected string         `json:"protected,omitempty"`
+	Header    map[string]any `json:"header,omitempty"`
+	Signature string         `json:"signature"`
+}
+
+// GeneralJWS is general JWS JSON serialization, see RFC 7515 section 7.2.1.
+type GeneralJWS struct {
+	Payload    string          `json:"payload"`
+	Signatures []JSONSignature `json:"signatures"`
+}
+
+// FlattenedJWS is flattened JWS JSON serialization, see RFC 7515 section 7.2.2.
+type FlattenedJWS struct {
+	Payload string `json:"payload"`
+	JSONSignature
+}
+
+// JSONSigner is signing method with unprotected header of its signature, the header must not have typ, alg and crit.
+type JSONSigner struct {
+	SigningMethod SigningMethod
+	Header        map[string]any
+}
+
+type JSONTokenCreator struct {
+	encoder Encoder
+	signers []JSONSigner
+}
+
+func NewJSONTokenCreator(encoder Encoder, signers ...JSONSigner) *JSONTokenCreator {
+	return &JSONTokenCreator{encoder: encoder, signers: signers}
+}
+
+func (c *JSONTokenCreator) CreateGeneral(payload Payload) (GeneralJWS, error) {
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return GeneralJWS{}, fmt.Errorf("failed marshal payload, %w", err)
+	}
+
+	encodedPayload := c.encoder.EncodeToString(rawPayload)
+	jws := GeneralJWS{
+		Payload:    encodedPayload,
+		Signatures: make([]JSONSignature, 0, len(c.signers)),
+	}
+	for _, signer := range c.signers {
+		signature, err := c.sign(signer, encodedPayload)
+		if err != nil {
+			return GeneralJWS{}, err
+		}
+		jws.Signatures = append(jws.Signatures, signature)
+	}
+
+	return jws, nil
+}
+
+func (c *JSONTokenCreator) CreateFlattened(payload Payload) (FlattenedJWS, error) {
+	if len(c.signers) != 1 {
+		return FlattenedJWS{}, ErrSingleSignatureRequired
+	}
+
+	jws, err := c.CreateGeneral(payload)
+	if err != nil {
+		return FlattenedJWS{}, err
+	}
+
+	return FlattenedJWS{Payload: jws.Payload, JSONSignature: jws.Signatures[0]}, nil
+}
+
+func (c *JSONTokenCreator) sign(signer JSONSigner, encodedPayload string) (JSONSignature, error) {
+	for _, name := range []string{"typ", "alg", "crit"} {
+		if _, ok := signer.Header[name]; ok {
+			return JSONSignature{}, fmt.Errorf("unprotected header has %s, %w", name, ErrDuplicateHeader)
+		}
+	}
+
+	headerString := MakeJwtHeader(signer.SigningMethod.Alg())
+	encodedHeader := c.encoder.EncodeToString([]byte(headerString))
+
+	sign, err := signer.SigningMethod.Sign(encodedHeader + "." + encodedPayload)
+	if err != nil {
+		return JSONSignature{}, err
+	}
+
+	return JSONSignature{
+		Protected: encodedHeader,
+		Header:    signer.Header,
+		Signature: c.encoder.EncodeToString(sign),
+	}, nil
+}
+
+// JSONToken is payload of JWS JSON token with headers of the verified signatures, protected and unprotected headers are merged.
+type JSONToken struct {
+	Headers []Header
+	Payload Payload
+}
+
+// JSONTokenParser parses general and flattened JWS JSON serialization.
+// Signature is checked with every signing method of its header alg, protected and unprotected headers must be disjoint
+// and crit is not supported.
+type JSONTokenParser struct {
+	decoder        Decoder
+	policy         VerificationPolicy
+	signingMethods []SigningMethod
+}
+
+func NewJSONTokenParser(decoder Decoder, policy VerificationPolicy, signingMethods ...SigningMethod) *JSONTokenParser {
+	return &JSONTokenParser{decoder: decoder, policy: policy, signingMethods: signingMethods}
+}
+
+func (p *JSONTokenParser) ParseJSON(data []byte) (JSONToken, error) {
+	headers, content, err := p.ParseJSONContent(data)
+	if err != nil {
+		return JSONToken{}, err
+	}
+
+	token := JSONToken{Headers: headers}
+	err = json.Unmarshal(content, &token.Payload)
+	if err != nil {
+		return JSONToken{}, fmt.Errorf("failed set payload, %w", ErrUnmarshalToken)
+	}
+
+	return token, nil
+}
+
+// ParseJSONContent verifies JWS JSON token with arbitrary payload and returns headers of the verified signatures and decoded content.
+func (p *JSONTokenParser) ParseJSONContent(data []byte) ([]Header, []byte, error) {
+	var jws struct {
+		Payload    *string         `json:"payload"`
+		Signatures []JSONSignature `json:"signatures"`
+		JSONSignature
+	}
+	err := json.Unmarshal(data, &jws)
+	if err != nil || jws.Payload == nil {
+		return nil, nil, ErrBadToken
+	}
+
+	signatures := jws.Signatures
+	if signatures == nil {
+		signatures = []JSONSignature{jws.JSONSignature}
+	} else if jws.Signature != "" || jws.Protected != "" || jws.Header != nil {
+		return nil, nil, ErrBadToken
+	}
+	if len(signatures) == 0 {
+		return nil, nil, ErrBadToken
+	}
+
+	headers := make([]Header, 0, len(signatures))
+	for _, signature := range signatures {
+		header, err := p.verifySignature(signature, *jws.Payload)
+		if err != nil {
+			if p.policy == VerifyAll {
+				return nil, nil, err
+			}
+			continue
+		}
+		headers = append(headers, header)
+	}
+	if len(headers) == 0 {
+		return nil, nil, ErrSignNotVerified
+	}
+
+	content, err := p.decoder.DecodeString(*jws.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed decode payload, %w", wrapTokenInvalid(ErrBadToken, err))
+	}
+
+	return headers, content, nil
+}
+
+func (p *JSONTokenParser) verifySignature(signature JSONSignature, encodedPayload string) (header Header, err error) {
+	header, err = p.decodeHeader(signature)
+	if err != nil {
+		return header, err
+	}
+	sign, err := p.decoder.DecodeString(signature.Signature)
+	if err != nil {
+		return header, fmt.Errorf("failed decode sign segment, %w", wrapTokenInvalid(ErrBadToken, err))
+	}
+
+	signed := signature.Protected + "." + encodedPayload
+	err = ErrWrongAlgoritm
+	for _, signingMethod := range p.signingMethods {
+		if signingMethod.Alg() != header.Alg {
+			continue
+		}
+		if signingMethod.Verify(signed, sign) == nil {
+			return header, nil
+		}
+		err = ErrSignNotVerified
+	}
+
+	return header, err
+}
+
+// decodeHeader merges protected and unprotected header of signature, protected header could be absent.
+func (p *JSONTokenParser) decodeHeader(signature JSONSignature) (header Header, err error) {
+	var protected map[string]json.RawMessage
+	if signature.Protected != "" {
+		rawHeader, err := p.decoder.DecodeString(signature.Protected)
+		if err != nil {
+			return header, fmt.Errorf("failed decode header, %w", wrapTokenInvalid(ErrBadToken, err))
+		}
+		err = json.Unmarshal(rawHeader, &protected)
+		if err == nil {
+			err = json.Unmarshal(rawHeader, &header)
+		}
+		if err != nil {
+			return header, fmt.Errorf("failed set header, %w", ErrUnmarshalToken)
+		}
+	}
+	if _, ok := protected["crit"]; ok {
+		return header, ErrUnsupportedCrit
+	}
+
+	if _, ok := signature.Header["crit"]; ok {
+		return header, fmt.Errorf("crit in unprotected header, %w", ErrBadToken)
+	}
+	for name := range signature.Header {
+		if _, ok := protected[name]; ok {
+			return header, fmt.Errorf("header parameter %s, %w", name, ErrDuplicateHeader)
+		}
+	}
+	if len(signature.Header) != 0 {
+		rawHeader, err := json.Marshal(signature.Header)
+		if err == nil {
+			err = json.Unmarshal(rawHeader, &header)
+		}
+		if err != nil {
+			return header, fmt.Errorf("failed set unprotected header, %w", wrapTokenInvalid(ErrBadToken, err))
+		}
+	}
+
+	if header.Alg == "" {
+		return header, fmt.Errorf("no alg in header, %w", ErrBadToken)
+	}
+	return header, nil
+}
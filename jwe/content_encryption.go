@@ -0,0 +1,195 @@
+package jwe
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrAuthenticationFailed = errors.New("content authentication failed")
+
+// ContentEncryption encrypts token plaintext with content encryption key, see RFC 7518 section 5.
+type ContentEncryption interface {
+	Enc() string
+	KeySize() int
+	Encrypt(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error)
+	Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+type AESGCM struct {
+	keySize int
+}
+
+func (a *AESGCM) KeySize() int {
+	return a.keySize
+}
+
+func (a *AESGCM) Encrypt(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	aead, err := a.aead(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	tagStart := len(sealed) - aead.Overhead()
+
+	return iv, sealed[:tagStart], sealed[tagStart:], nil
+}
+
+func (a *AESGCM) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := a.aead(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, ErrAuthenticationFailed
+	}
+
+	sealed := make([]byte, 0, len(ciphertext)+len(tag))
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, tag...)
+
+	plaintext, err := aead.Open(nil, iv, sealed, aad)
+	if err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+
+	return plaintext, nil
+}
+
+func (a *AESGCM) aead(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != a.keySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+type A128GCM struct{ *AESGCM }
+
+func NewA128GCM() *A128GCM {
+	return &A128GCM{AESGCM: &AESGCM{keySize: 16}}
+}
+
+func (a *A128GCM) Enc() string {
+	return "A128GCM"
+}
+
+type A256GCM struct{ *AESGCM }
+
+func NewA256GCM() *A256GCM {
+	return &A256GCM{AESGCM: &AESGCM{keySize: 32}}
+}
+
+func (a *A256GCM) Enc() string {
+	return "A256GCM"
+}
+
+// AESCBCHMAC is AES-CBC encryption with HMAC authentication, see RFC 7518 section 5.2.
+// Content encryption key is MAC key followed by encryption key of the same size.
+type AESCBCHMAC struct {
+	keySize int
+	hash    crypto.Hash
+}
+
+func (a *AESCBCHMAC) KeySize() int {
+	return a.keySize
+}
+
+func (a *AESCBCHMAC) Encrypt(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	if len(cek) != a.keySize {
+		return nil, nil, nil, ErrInvalidKeySize
+	}
+	macKey, encKey := cek[:a.keySize/2], cek[a.keySize/2:]
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	copy(ciphertext[len(plaintext):], bytes.Repeat([]byte{byte(padding)}, padding))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, a.tag(macKey, aad, iv, ciphertext), nil
+}
+
+func (a *AESCBCHMAC) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != a.keySize {
+		return nil, ErrInvalidKeySize
+	}
+	macKey, encKey := cek[:a.keySize/2], cek[a.keySize/2:]
+
+	if !hmac.Equal(tag, a.tag(macKey, aad, iv, ciphertext)) {
+		return nil, ErrAuthenticationFailed
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrAuthenticationFailed
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrAuthenticationFailed
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, ErrAuthenticationFailed
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func (a *AESCBCHMAC) tag(macKey, aad, iv, ciphertext []byte) []byte {
+	var al [8]byte
+	binary.BigEndian.PutUint64(al[:], uint64(len(aad))*8)
+
+	mac := hmac.New(a.hash.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al[:])
+
+	return mac.Sum(nil)[:a.keySize/2]
+}
+
+type A128CBCHS256 struct{ *AESCBCHMAC }
+
+func NewA128CBCHS256() *A128CBCHS256 {
+	return &A128CBCHS256{AESCBCHMAC: &AESCBCHMAC{keySize: 32, hash: crypto.SHA256}}
+}
+
+func (a *A128CBCHS256) Enc() string {
+	return "A128CBC-HS256"
+}
@@ -0,0 +1,60 @@
+package jwe
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/amidgo/jwt"
+)
+
+// TokenCreator creates JWE tokens in compact serialization, see RFC 7516 section 7.1.
+type TokenCreator struct {
+	encoder           jwt.Encoder
+	keyManager        KeyManager
+	contentEncryption ContentEncryption
+}
+
+func NewTokenCreator(encoder jwt.Encoder, keyManager KeyManager, contentEncryption ContentEncryption) *TokenCreator {
+	return &TokenCreator{
+		encoder:           encoder,
+		keyManager:        keyManager,
+		contentEncryption: contentEncryption,
+	}
+}
+
+func (c *TokenCreator) CreateToken(payload jwt.Payload) (string, error) {
+	rawPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed marshal payload, %w", err)
+	}
+
+	return c.Encrypt(Header{Typ: "JWT"}, rawPayload)
+}
+
+// Encrypt encrypts plaintext, alg and enc of the header are set by the creator.
+func (c *TokenCreator) Encrypt(header Header, plaintext []byte) (string, error) {
+	header.Alg = c.keyManager.Alg()
+	header.Enc = c.contentEncryption.Enc()
+
+	cek, encryptedKey, err := c.keyManager.EncryptKey(&header, c.contentEncryption.KeySize())
+	if err != nil {
+		return "", fmt.Errorf("failed encrypt key, %w", err)
+	}
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed marshal header, %w", err)
+	}
+	encodedHeader := c.encoder.EncodeToString(rawHeader)
+
+	iv, ciphertext, tag, err := c.contentEncryption.Encrypt(cek, plaintext, []byte(encodedHeader))
+	if err != nil {
+		return "", fmt.Errorf("failed encrypt content, %w", err)
+	}
+
+	return encodedHeader + "." +
+		c.encoder.EncodeToString(encryptedKey) + "." +
+		c.encoder.EncodeToString(iv) + "." +
+		c.encoder.EncodeToString(ciphertext) + "." +
+		c.encoder.EncodeToString(tag), nil
+}
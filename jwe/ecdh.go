@@ -0,0 +1,206 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+
+	"github.com/amidgo/jwt/jwk"
+)
+
+var ErrInvalidEphemeralKey = errors.New("invalid ephemeral public key")
+
+// ECDH agrees on a key with ephemeral-static ECDH, see RFC 7518 section 4.6.
+// Without key wrapping the agreed key is content encryption key, otherwise it wraps random content encryption key.
+type ECDH struct {
+	public      *ecdsa.PublicKey
+	private     *ecdsa.PrivateKey
+	alg         string
+	keyWrapSize int
+}
+
+func (e *ECDH) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	recipient, err := e.public.ECDH()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ephemeral, err := ecdsa.GenerateKey(e.public.Curve, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	epk, err := jwk.NewECPublicKey(&ephemeral.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	header.Epk = &epk
+
+	ephemeralPrivate, err := ephemeral.ECDH()
+	if err != nil {
+		return nil, nil, err
+	}
+	z, err := ephemeralPrivate.ECDH(recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if e.keyWrapSize == 0 {
+		cek, err := e.deriveKey(*header, z, header.Enc, cekSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cek, nil, nil
+	}
+
+	kek, err := e.deriveKey(*header, z, e.alg, e.keyWrapSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedKey, err := wrapKey(kek, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (e *ECDH) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if e.private == nil {
+		return nil, ErrMissingPrivateKey
+	}
+	if header.Epk == nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+
+	epk, err := header.Epk.ECPublicKey()
+	if err != nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+	if epk.Curve != e.private.Curve {
+		return nil, ErrInvalidEphemeralKey
+	}
+	ephemeral, err := epk.ECDH()
+	if err != nil {
+		return nil, ErrInvalidEphemeralKey
+	}
+	private, err := e.private.ECDH()
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := private.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.keyWrapSize == 0 {
+		if len(encryptedKey) != 0 {
+			return nil, ErrDecryptionFailed
+		}
+		return e.deriveKey(header, z, header.Enc, cekSize)
+	}
+
+	kek, err := e.deriveKey(header, z, e.alg, e.keyWrapSize)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := unwrapKey(kek, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(cek) != cekSize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return cek, nil
+}
+
+func (e *ECDH) deriveKey(header Header, z []byte, algID string, keySize int) ([]byte, error) {
+	apu, err := base64.RawURLEncoding.DecodeString(header.Apu)
+	if err != nil {
+		return nil, err
+	}
+	apv, err := base64.RawURLEncoding.DecodeString(header.Apv)
+	if err != nil {
+		return nil, err
+	}
+
+	return concatKDF(z, []byte(algID), apu, apv, keySize), nil
+}
+
+type ECDHES struct{ *ECDH }
+
+func NewECDHES(private *ecdsa.PrivateKey) *ECDHES {
+	return &ECDHES{ECDH: &ECDH{public: &private.PublicKey, private: private, alg: "ECDH-ES"}}
+}
+
+// NewECDHESEncrypter returns ECDH-ES key manager that can only encrypt to the public key.
+func NewECDHESEncrypter(public *ecdsa.PublicKey) *ECDHES {
+	return &ECDHES{ECDH: &ECDH{public: public, alg: "ECDH-ES"}}
+}
+
+func (e *ECDHES) Alg() string {
+	return "ECDH-ES"
+}
+
+type ECDHESA128KW struct{ *ECDH }
+
+func NewECDHESA128KW(private *ecdsa.PrivateKey) *ECDHESA128KW {
+	return &ECDHESA128KW{ECDH: &ECDH{public: &private.PublicKey, private: private, alg: "ECDH-ES+A128KW", keyWrapSize: 16}}
+}
+
+// NewECDHESA128KWEncrypter returns ECDH-ES+A128KW key manager that can only encrypt to the public key.
+func NewECDHESA128KWEncrypter(public *ecdsa.PublicKey) *ECDHESA128KW {
+	return &ECDHESA128KW{ECDH: &ECDH{public: public, alg: "ECDH-ES+A128KW", keyWrapSize: 16}}
+}
+
+func (e *ECDHESA128KW) Alg() string {
+	return "ECDH-ES+A128KW"
+}
+
+type ECDHESA256KW struct{ *ECDH }
+
+func NewECDHESA256KW(private *ecdsa.PrivateKey) *ECDHESA256KW {
+	return &ECDHESA256KW{ECDH: &ECDH{public: &private.PublicKey, private: private, alg: "ECDH-ES+A256KW", keyWrapSize: 32}}
+}
+
+// NewECDHESA256KWEncrypter returns ECDH-ES+A256KW key manager that can only encrypt to the public key.
+func NewECDHESA256KWEncrypter(public *ecdsa.PublicKey) *ECDHESA256KW {
+	return &ECDHESA256KW{ECDH: &ECDH{public: public, alg: "ECDH-ES+A256KW", keyWrapSize: 32}}
+}
+
+func (e *ECDHESA256KW) Alg() string {
+	return "ECDH-ES+A256KW"
+}
+
+// concatKDF implements Concat KDF of NIST SP 800-56A with SHA-256, see RFC 7518 section 4.6.2.
+func concatKDF(z, algID, apu, apv []byte, keySize int) []byte {
+	otherInfo := make([]byte, 0, 16+len(algID)+len(apu)+len(apv))
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(algID)))
+	otherInfo = append(otherInfo, algID...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(apu)))
+	otherInfo = append(otherInfo, apu...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(apv)))
+	otherInfo = append(otherInfo, apv...)
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(keySize*8))
+
+	key := make([]byte, 0, keySize+sha256.Size)
+	for counter := uint32(1); len(key) < keySize; counter++ {
+		hasher := sha256.New()
+		var round [4]byte
+		binary.BigEndian.PutUint32(round[:], counter)
+		hasher.Write(round[:])
+		hasher.Write(z)
+		hasher.Write(otherInfo)
+		key = hasher.Sum(key)
+	}
+
+	return key[:keySize]
+}
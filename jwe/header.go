@@ -0,0 +1,29 @@
+package jwe
+
+import (
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+)
+
+var (
+	ErrDecryptionFailed  jwt.TokenInvalidError = "decryption_failed"
+	ErrWrongEncryption   jwt.TokenInvalidError = "wrong_encryption"
+	ErrUnsupportedHeader jwt.TokenInvalidError = "unsupported_header"
+)
+
+// Header is JOSE header of JWE token, see RFC 7516 section 4.
+type Header struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Typ string `json:"typ,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Zip string `json:"zip,omitempty"`
+	// Crit lists extension header parameters that must be understood, see RFC 7516 section 4.1.13.
+	Crit []string `json:"crit,omitempty"`
+
+	// Epk, Apu and Apv are ECDH-ES key agreement parameters, see RFC 7518 section 4.6.1.
+	Epk *jwk.Key `json:"epk,omitempty"`
+	Apu string   `json:"apu,omitempty"`
+	Apv string   `json:"apv,omitempty"`
+}
@@ -0,0 +1,202 @@
+package jwe_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwe"
+	"gotest.tools/v3/assert"
+)
+
+func b64(s string) []byte {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func b64Int(s string) *big.Int {
+	return new(big.Int).SetBytes(b64(s))
+}
+
+// rfc7516RSAKey is RSA key of RFC 7516 appendix A.1.
+func rfc7516RSAKey() *rsa.PrivateKey {
+	key := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{
+			N: b64Int("oahUIoWw0K0usKNuOR6H4wkf4oBUXHTxRvgb48E-BVvxkeDNjbC4he8rUWcJoZmds2h7M70imEVhRU5djINXtqllXI4DFqcI1DgjT9LewND8MW2Krf3Spsk_ZkoFnilakGygTwpZ3uesH-PFABNIUYpOiN15dsQRkgr0vEhxN92i2asbOenSZeyaxziK72UwxrrKoExv6kc5twXTq4h-QChLOln0_mtUZwfsRaMStPs6mS6XrgxnxbWhojf663tuEQueGC-FCMfra36C9knDFGzKsNa7LZK2djYgyD3JR_MB_4NUJW_TqOQtwHYbxevoJArm-L5StowjzGy-_bq6Gw"),
+			E: 65537,
+		},
+		D: b64Int("kLdtIj6GbDks_ApCSTYQtelcNttlKiOyPzMrXHeI-yk1F7-kpDxY4-WY5NWV5KntaEeXS1j82E375xxhWMHXyvjYecPT9fpwR_M9gV8n9Hrh2anTpTD93Dt62ypW3yDsJzBnTnrYu1iwWRgBKrEYY46qAZIrA2xAwnm2X7uGR1hghkqDp0Vqj3kbSCz1XyfCs6_LehBwtxHIyh8Ripy40p24moOAbgxVw3rxT_vlt3UVe4WO3JkJOzlpUf-KTVI2Ptgm-dARxTEtE-id-4OJr0h-K-VFs3VSndVTIznSxfyrj8ILL6MG_Uv8YAu7VILSB3lOW085-4qE3DzgrTjgyQ"),
+		Primes: []*big.Int{
+			b64Int("1r52Xk46c-LsfB5P442p7atdPUrxQSy4mti_tZI3Mgf2EuFVbUoDBvaRQ-SWxkbkmoEzL7JXroSBjSrK3YIQgYdMgyAEPTPjXv_hI2_1eTSPVZfzL0lffNn03IXqWF5MDFuoUYE0hzb2vhrlN_rKrbfDIwUbTrjjgieRbwC6Cl0"),
+			b64Int("wLb35x7hmQWZsWJmB_vle87ihgZ19S8lBEROLIsZG4ayZVe9Hi9gDVCOBmUDdaDYVTSNx_8Fyw1YYa9XGrGnDew00J28cRUoeBB_jKI1oma0Orv1T9aXIWxKwd4gvxFImOWr3QRL9KEBRzk2RatUBnmDZJTIAfwTs0g68UZHvtc"),
+		},
+	}
+	key.Precompute()
+	return key
+}
+
+func TestRFC7516RSAOAEP(t *testing.T) {
+	const token = "eyJhbGciOiJSU0EtT0FFUCIsImVuYyI6IkEyNTZHQ00ifQ.OKOawDo13gRp2ojaHV7LFpZcgV7T6DVZKTyKOMTYUmKoTCVJRgckCL9kiMT03JGeipsEdY3mx_etLbbWSrFr05kLzcSr4qKAq7YN7e9jwQRb23nfa6c9d-StnImGyFDbSv04uVuxIp5Zms1gNxKKK2Da14B8S4rzVRltdYwam_lDp5XnZAYpQdb76FdIKLaVmqgfwX7XWRxv2322i-vDxRfqNzo_tETKzpVLzfiwQyeyPGLBIO56YJ7eObdv0je81860ppamavo35UgoRdbYaBcoh9QcfylQr66oc6vFWXRcZ_ZT2LawVCWTIy3brGPi6UklfCpIMfIjf7iGdXKHzg.48V1_ALb6US04U3b.5eym8TW_c8SuK0ltJ3rpYIzOeDQz7TALvtu6UG9oMo4vpzs9tX_EFShS8iB7j6jiSdiwkIr3ajwQzaBtQD_A.XFBoMYUZodetZdvTiFvSkQ"
+
+	parser := jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewRSAOAEP(rfc7516RSAKey()), jwe.NewA256GCM())
+
+	header, plaintext, err := parser.Decrypt(token)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, header, jwe.Header{Alg: "RSA-OAEP", Enc: "A256GCM"})
+	assert.Equal(t, string(plaintext), "The true sign of intelligence is not knowledge but imagination.")
+
+	_, _, err = parser.Decrypt(token[:len(token)-2] + "AA")
+	assert.ErrorIs(t, err, jwe.ErrDecryptionFailed)
+}
+
+func TestRFC7516A128CBCHS256(t *testing.T) {
+	// content encryption values of RFC 7516 appendix A.2
+	cek := []byte{
+		4, 211, 31, 197, 84, 157, 252, 254, 11, 100, 157, 250, 63, 170, 106, 206,
+		107, 124, 212, 45, 111, 107, 9, 219, 200, 177, 0, 240, 143, 156, 44, 207,
+	}
+	iv := []byte{3, 22, 60, 12, 43, 67, 104, 105, 108, 108, 105, 99, 111, 116, 104, 101}
+	aad := []byte("eyJhbGciOiJSU0ExXzUiLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0")
+	ciphertext := []byte{
+		40, 57, 83, 181, 119, 33, 133, 148, 198, 185, 243, 24, 152, 230, 6,
+		75, 129, 223, 127, 19, 210, 82, 183, 230, 168, 33, 215, 104, 143,
+		112, 56, 102,
+	}
+	tag := []byte{246, 17, 244, 190, 4, 95, 98, 3, 231, 0, 115, 157, 242, 203, 100, 191}
+
+	enc := jwe.NewA128CBCHS256()
+
+	plaintext, err := enc.Decrypt(cek, iv, ciphertext, tag, aad)
+	assert.NilError(t, err)
+	assert.Equal(t, string(plaintext), "Live long and prosper.")
+
+	tag[0] ^= 1
+	_, err = enc.Decrypt(cek, iv, ciphertext, tag, aad)
+	assert.ErrorIs(t, err, jwe.ErrAuthenticationFailed)
+}
+
+func TestCreateParse(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+
+	key256 := make([]byte, 32)
+	_, err = rand.Read(key256)
+	assert.NilError(t, err)
+
+	payload := jwt.Payload{"sub": "user", "email": "user@example.com"}
+
+	cases := []struct {
+		name       string
+		encrypter  jwe.KeyManager
+		decrypter  jwe.KeyManager
+		encryption jwe.ContentEncryption
+
+		encrypterParseErr error
+	}{
+		{"RSA-OAEP A128GCM", jwe.NewRSAOAEPEncrypter(&private.PublicKey), jwe.NewRSAOAEP(private), jwe.NewA128GCM(), jwe.ErrDecryptionFailed},
+		{"RSA-OAEP A256GCM", jwe.NewRSAOAEPEncrypter(&private.PublicKey), jwe.NewRSAOAEP(private), jwe.NewA256GCM(), jwe.ErrDecryptionFailed},
+		{"RSA-OAEP-256 A128CBC-HS256", jwe.NewRSAOAEP256Encrypter(&private.PublicKey), jwe.NewRSAOAEP256(private), jwe.NewA128CBCHS256(), jwe.ErrDecryptionFailed},
+		{"dir A256GCM", jwe.NewDirect(key256), jwe.NewDirect(key256), jwe.NewA256GCM(), nil},
+		{"dir A128CBC-HS256", jwe.NewDirect(key256), jwe.NewDirect(key256), jwe.NewA128CBCHS256(), nil},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			creator := jwe.NewTokenCreator(base64.RawURLEncoding, cs.encrypter, cs.encryption)
+			parser := jwe.NewTokenParser(base64.RawURLEncoding, cs.decrypter, cs.encryption)
+
+			accessToken, err := creator.CreateToken(payload)
+			assert.NilError(t, err)
+
+			token, err := parser.ParseToken(accessToken)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, token, jwt.NewToken(jwt.Header{Type: "JWT", Alg: cs.encrypter.Alg()}, payload))
+
+			_, err = jwe.NewTokenParser(base64.RawURLEncoding, cs.encrypter, cs.encryption).ParseToken(accessToken)
+			assert.ErrorIs(t, err, cs.encrypterParseErr)
+		})
+	}
+}
+
+func TestParseRejected(t *testing.T) {
+	key := make([]byte, 16)
+	creator := jwe.NewTokenCreator(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM())
+
+	accessToken, err := creator.CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	withHeader := func(header string) string {
+		rawToken, err := jwe.ParseRawToken(accessToken)
+		assert.NilError(t, err)
+		rawToken[0] = header
+		return strings.Join(rawToken[:], ".")
+	}
+
+	cases := []struct {
+		name        string
+		parser      *jwe.TokenParser
+		token       string
+		expectedErr error
+	}{
+		{
+			name:        "bad token",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM()),
+			token:       "a.b.c",
+			expectedErr: jwt.ErrBadToken,
+		},
+		{
+			name:        "wrong encryption",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA256GCM()),
+			token:       accessToken,
+			expectedErr: jwe.ErrWrongEncryption,
+		},
+		{
+			name:        "wrong algoritm",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewRSAOAEP256Encrypter(nil), jwe.NewA128GCM()),
+			token:       accessToken,
+			expectedErr: jwt.ErrWrongAlgoritm,
+		},
+		{
+			name:        "wrong key",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect([]byte("0123456789abcdef")), jwe.NewA128GCM()),
+			token:       accessToken,
+			expectedErr: jwe.ErrDecryptionFailed,
+		},
+		{
+			name:        "bad header encoding",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM()),
+			token:       withHeader("!"),
+			expectedErr: jwt.ErrBadToken,
+		},
+		{
+			name:        "bad header json",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM()),
+			token:       withHeader(base64.RawURLEncoding.EncodeToString([]byte("{"))),
+			expectedErr: jwt.ErrUnmarshalToken,
+		},
+		{
+			name:        "unknown crit",
+			parser:      jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM()),
+			token:       withHeader(base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"dir","enc":"A128GCM","crit":["exp"],"exp":1}`))),
+			expectedErr: jwt.ErrUnsupportedCrit,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := cs.parser.ParseToken(cs.token)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+	}
+
+	var parseErr *jwt.ParseError
+	_, err = jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewDirect(key), jwe.NewA128GCM()).ParseToken(withHeader("!"))
+	assert.Assert(t, errors.As(err, &parseErr))
+	assert.Equal(t, parseErr.Stage, jwt.StageDecodeHeader)
+}
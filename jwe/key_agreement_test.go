@@ -0,0 +1,289 @@
+package jwe_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwe"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func fromHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestRFC3394KeyWrap(t *testing.T) {
+	cases := []struct {
+		name       string
+		keyManager jwe.KeyManager
+		wrapped    []byte
+		key        []byte
+	}{
+		{
+			name:       "A128KW",
+			keyManager: jwe.NewA128KW(fromHex("000102030405060708090A0B0C0D0E0F")),
+			wrapped:    fromHex("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5"),
+			key:        fromHex("00112233445566778899AABBCCDDEEFF"),
+		},
+		{
+			name:       "A256KW",
+			keyManager: jwe.NewA256KW(fromHex("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")),
+			wrapped:    fromHex("28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21"),
+			key:        fromHex("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F"),
+		},
+	}
+
+	for _, cs := range cases {
+		key, err := cs.keyManager.DecryptKey(jwe.Header{}, cs.wrapped, len(cs.key))
+		assert.NilError(t, err, cs.name)
+		assert.DeepEqual(t, key, cs.key)
+
+		cs.wrapped[0] ^= 1
+		_, err = cs.keyManager.DecryptKey(jwe.Header{}, cs.wrapped, len(cs.key))
+		assert.ErrorIs(t, err, jwe.ErrKeyUnwrapFailed, cs.name)
+	}
+}
+
+func TestRFC7516A128KW(t *testing.T) {
+	const token = "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0.6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ.AxY8DCtDaGlsbGljb3RoZQ.KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY.U0m_YmjN04DJvceFICbCVQ"
+
+	parser := jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewA128KW(b64("GawgguFyGrWKav7AX4VKUg")), jwe.NewA128CBCHS256())
+
+	header, plaintext, err := parser.Decrypt(token)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, header, jwe.Header{Alg: "A128KW", Enc: "A128CBC-HS256"})
+	assert.Equal(t, string(plaintext), "Live long and prosper.")
+}
+
+func TestRFC7518ECDHES(t *testing.T) {
+	bob := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     b64Int("weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ"),
+			Y:     b64Int("e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck"),
+		},
+		D: b64Int("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"),
+	}
+	header := jwe.Header{
+		Alg: "ECDH-ES",
+		Enc: "A128GCM",
+		Epk: &jwk.Key{
+			Kty: "EC",
+			Crv: "P-256",
+			X:   "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+			Y:   "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+		},
+		Apu: "QWxpY2U",
+		Apv: "Qm9i",
+	}
+
+	cek, err := jwe.NewECDHES(bob).DecryptKey(header, nil, 16)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cek, b64("VqqN6vgjbSBcIijNcacQGg"))
+}
+
+// rfc7520Plaintext is plaintext of RFC 7520 section 5 examples.
+const rfc7520Plaintext = "You can trust us to stick with you through thick and thin\u2013to the bitter end. " +
+	"And you can trust us to keep any secret of yours\u2013closer than you keep it yourself. " +
+	"But you cannot trust us to let you face trouble alone, and go off without a word. We are your friends, Frodo."
+
+func TestRFC7520(t *testing.T) {
+	cases := []struct {
+		name           string
+		token          string
+		keyManager     jwe.KeyManager
+		encryption     jwe.ContentEncryption
+		expectedHeader jwe.Header
+	}{
+		{
+			name: "5.4 ECDH-ES+A128KW and A128GCM",
+			token: "eyJhbGciOiJFQ0RILUVTK0ExMjhLVyIsImtpZCI6InBlcmVncmluLnRvb2tAdHVja2Jvcm91Z2guZXhhbXBsZSIsImVwayI6eyJrdHkiOiJFQyIsImNydiI6IlAtMzg0IiwieCI6InVCbzRrSFB3Nmtiang1bDB4b3dyZF9vWXpCbWF6LUdLRlp1NHhBRkZrYllpV2d1dEVLNml1RURzUTZ3TmROZzMiLCJ5Ijoic3AzcDVTR2haVkMyZmFYdW1JLWU5SlUyTW84S3BvWXJGRHI1eVBOVnRXNFBnRXdaT3lRVEEtSmRhWTh0YjdFMCJ9LCJlbmMiOiJBMTI4R0NNIn0" +
+				".0DJjBXri_kBcC46IkU5_Jk9BqaQeHdv2" +
+				".mH-G2zVqgztUtnW_" +
+				".tkZuOO9h95OgHJmkkrfLBisku8rGf6nzVxhRM3sVOhXgz5NJ76oID7lpnAi_cPWJRCjSpAaUZ5dOR3Spy7QuEkmKx8-3RCMhSYMzsXaEwDdXta9Mn5B7cCBoJKB0IgEnj_qfo1hIi-uEkUpOZ8aLTZGHfpl05jMwbKkTe2yK3mjF6SBAsgicQDVCkcY9BLluzx1RmC3ORXaM0JaHPB93YcdSDGgpgBWMVrNU1ErkjcMqMoT_wtCex3w03XdLkjXIuEr2hWgeP-nkUZTPU9EoGSPj6fAS-bSz87RCPrxZdj_iVyC6QWcqAu07WNhjzJEPc4jVntRJ6K53NgPQ5p99l3Z408OUqj4ioYezbS6vTPlQ" +
+				".WuGzxmcreYjpHGJoa17EBg",
+			keyManager: jwe.NewECDHESA128KW(&ecdsa.PrivateKey{
+				PublicKey: ecdsa.PublicKey{
+					Curve: elliptic.P384(),
+					X:     b64Int("YU4rRUzdmVqmRtWOs2OpDE_T5fsNIodcG8G5FWPrTPMyxpzsSOGaQLpe2FpxBmu2"),
+					Y:     b64Int("A8-yxCHxkfBz3hKZfI1jUYMjUhsEveZ9THuwFjH2sCNdtksRJU7D5-SkgaFL1ETP"),
+				},
+				D: b64Int("iTx2pk7wW-GqJkHcEkFQb2EFyYcO7RugmaW3mRrQVAOUiPommT0IdnYK2xDlZh-j"),
+			}),
+			encryption: jwe.NewA128GCM(),
+			expectedHeader: jwe.Header{
+				Alg: "ECDH-ES+A128KW",
+				Enc: "A128GCM",
+				Kid: "peregrin.took@tuckborough.example",
+				Epk: &jwk.Key{
+					Kty: "EC",
+					Crv: "P-384",
+					X:   "uBo4kHPw6kbjx5l0xowrd_oYzBmaz-GKFZu4xAFFkbYiWgutEK6iuEDsQ6wNdNg3",
+					Y:   "sp3p5SGhZVC2faXumI-e9JU2Mo8KpoYrFDr5yPNVtW4PgEwZOyQTA-JdaY8tb7E0",
+				},
+			},
+		},
+		{
+			name: "5.5 ECDH-ES and A128CBC-HS256",
+			token: "eyJhbGciOiJFQ0RILUVTIiwia2lkIjoibWVyaWFkb2MuYnJhbmR5YnVja0BidWNrbGFuZC5leGFtcGxlIiwiZXBrIjp7Imt0eSI6IkVDIiwiY3J2IjoiUC0yNTYiLCJ4IjoibVBVS1RfYkFXR0hJaGcwVHBqanFWc1AxclhXUXVfdndWT0hIdE5rZFlvQSIsInkiOiI4QlFBc0ltR2VBUzQ2ZnlXdzVNaFlmR1RUMElqQnBGdzJTUzM0RHY0SXJzIn0sImVuYyI6IkExMjhDQkMtSFMyNTYifQ" +
+				"." +
+				".yc9N8v5sYyv3iGQT926IUg" +
+				".BoDlwPnTypYq-ivjmQvAYJLb5Q6l-F3LIgQomlz87yW4OPKbWE1zSTEFjDfhU9IPIOSA9Bml4m7iDFwA-1ZXvHteLDtw4R1XRGMEsDIqAYtskTTmzmzNa-_q4F_evAPUmwlO-ZG45Mnq4uhM1fm_D9rBtWolqZSF3xGNNkpOMQKF1Cl8i8wjzRli7-IXgyirlKQsbhhqRzkv8IcY6aHl24j03C-AR2le1r7URUhArM79BY8soZU0lzwI-sD5PZ3l4NDCCei9XkoIAfsXJWmySPoeRb2Ni5UZL4mYpvKDiwmyzGd65KqVw7MsFfI_K767G9C9Azp73gKZD0DyUn1mn0WW5LmyX_yJ-3AROq8p1WZBfG-ZyJ6195_JGG2m9Csg" +
+				".WCCkNa-x4BeB9hIDIfFuhg",
+			keyManager: jwe.NewECDHES(&ecdsa.PrivateKey{
+				PublicKey: ecdsa.PublicKey{
+					Curve: elliptic.P256(),
+					X:     b64Int("Ze2loSV3wrroKUN_4zhwGhCqo3Xhu1td4QjeQ5wIVR0"),
+					Y:     b64Int("HlLtdXARY_f55A3fnzQbPcm6hgr34Mp8p-nuzQCE0Zw"),
+				},
+				D: b64Int("r_kHyZ-a06rmxM3yESK84r1otSg-aQcVStkRhA-iCM8"),
+			}),
+			encryption: jwe.NewA128CBCHS256(),
+			expectedHeader: jwe.Header{
+				Alg: "ECDH-ES",
+				Enc: "A128CBC-HS256",
+				Kid: "meriadoc.brandybuck@buckland.example",
+				Epk: &jwk.Key{
+					Kty: "EC",
+					Crv: "P-256",
+					X:   "mPUKT_bAWGHIhg0TpjjqVsP1rXWQu_vwVOHHtNkdYoA",
+					Y:   "8BQAsImGeAS46fyWw5MhYfGTT0IjBpFw2SS34Dv4Irs",
+				},
+			},
+		},
+		{
+			name: "5.6 dir and A128GCM",
+			token: "eyJhbGciOiJkaXIiLCJraWQiOiI3N2M3ZTJiOC02ZTEzLTQ1Y2YtODY3Mi02MTdiNWI0NTI0M2EiLCJlbmMiOiJBMTI4R0NNIn0" +
+				"." +
+				".refa467QzzKx6QAB" +
+				".JW_i_f52hww_ELQPGaYyeAB6HYGcR559l9TYnSovc23XJoBcW29rHP8yZOZG7YhLpT1bjFuvZPjQS-m0IFtVcXkZXdH_lr_FrdYt9HRUYkshtrMmIUAyGmUnd9zMDB2n0cRDIHAzFVeJUDxkUwVAE7_YGRPdcqMyiBoCO-FBdE-Nceb4h3-FtBP-c_BIwCPTjb9o0SbdcdREEMJMyZBH8ySWMVi1gPD9yxi-aQpGbSv_F9N4IZAxscj5g-NJsUPbjk29-s7LJAGb15wEBtXphVCgyy53CoIKLHHeJHXex45Uz9aKZSRSInZI-wjsY0yu3cT4_aQ3i1o-tiE-F8Ios61EKgyIQ4CWao8PFMj8TTnp" +
+				".vbb32Xvllea2OtmHAdccRQ",
+			keyManager: jwe.NewDirect(b64("XctOhJAkA-pD9Lh7ZgW_2A")),
+			encryption: jwe.NewA128GCM(),
+			expectedHeader: jwe.Header{
+				Alg: "dir",
+				Enc: "A128GCM",
+				Kid: "77c7e2b8-6e13-45cf-8672-617b5b45243a",
+			},
+		},
+		{
+			name: "5.8 A128KW and A128GCM",
+			token: "eyJhbGciOiJBMTI4S1ciLCJraWQiOiI4MWIyMDk2NS04MzMyLTQzZDktYTQ2OC04MjE2MGFkOTFhYzgiLCJlbmMiOiJBMTI4R0NNIn0" +
+				".CBI6oDw8MydIx1IBntf_lQcw2MmJKIQx" +
+				".Qx0pmsDa8KnJc9Jo" +
+				".AwliP-KmWgsZ37BvzCefNen6VTbRK3QMA4TkvRkH0tP1bTdhtFJgJxeVmJkLD61A1hnWGetdg11c9ADsnWgL56NyxwSYjU1ZEHcGkd3EkU0vjHi9gTlb90qSYFfeF0LwkcTtjbYKCsiNJQkcIp1yeM03OmuiYSoYJVSpf7ej6zaYcMv3WwdxDFl8REwOhNImk2Xld2JXq6BR53TSFkyT7PwVLuq-1GwtGHlQeg7gDT6xW0JqHDPn_H-puQsmthc9Zg0ojmJfqqFvETUxLAF-KjcBTS5dNy6egwkYtOt8EIHK-oEsKYtZRaa8Z7MOZ7UGxGIMvEmxrGCPeJa14slv2-gaqK0kEThkaSqdYw0FkQZF" +
+				".ER7MWJZ1FBI_NKvn7Zb1Lw",
+			keyManager: jwe.NewA128KW(b64("GZy6sIZ6wl9NJOKB-jnmVQ")),
+			encryption: jwe.NewA128GCM(),
+			expectedHeader: jwe.Header{
+				Alg: "A128KW",
+				Enc: "A128GCM",
+				Kid: "81b20965-8332-43d9-a468-82160ad91ac8",
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		header, plaintext, err := jwe.NewTokenParser(base64.RawURLEncoding, cs.keyManager, cs.encryption).Decrypt(cs.token)
+		assert.NilError(t, err, cs.name)
+		assert.DeepEqual(t, header, cs.expectedHeader)
+		assert.Equal(t, string(plaintext), rfc7520Plaintext, cs.name)
+	}
+}
+
+func loadECKeys(t *testing.T, name string) (*ecdsa.PrivateKey, *ecdsa.PublicKey) {
+	privatePEM, err := os.ReadFile("../signingmethods/testdata/" + name + "-private.pem")
+	assert.NilError(t, err)
+	publicPEM, err := os.ReadFile("../signingmethods/testdata/" + name + "-public.pem")
+	assert.NilError(t, err)
+
+	private, err := signingmethods.ParseECPrivateKeyFromPEM(privatePEM)
+	assert.NilError(t, err)
+	public, err := signingmethods.ParseECPublicKeyFromPEM(publicPEM)
+	assert.NilError(t, err)
+
+	return private, public
+}
+
+func TestECDHCreateParse(t *testing.T) {
+	payload := jwt.Payload{"sub": "partner"}
+
+	for _, name := range []string{"ec256", "ec384", "ec512"} {
+		private, public := loadECKeys(t, name)
+
+		cases := []struct {
+			encrypter jwe.KeyManager
+			decrypter jwe.KeyManager
+		}{
+			{jwe.NewECDHESEncrypter(public), jwe.NewECDHES(private)},
+			{jwe.NewECDHESA128KWEncrypter(public), jwe.NewECDHESA128KW(private)},
+			{jwe.NewECDHESA256KWEncrypter(public), jwe.NewECDHESA256KW(private)},
+		}
+
+		for _, cs := range cases {
+			for _, enc := range []jwe.ContentEncryption{jwe.NewA128GCM(), jwe.NewA256GCM(), jwe.NewA128CBCHS256()} {
+				t.Run(name+" "+cs.encrypter.Alg()+" "+enc.Enc(), func(t *testing.T) {
+					creator := jwe.NewTokenCreator(base64.RawURLEncoding, cs.encrypter, enc)
+					parser := jwe.NewTokenParser(base64.RawURLEncoding, cs.decrypter, enc)
+
+					accessToken, err := creator.CreateToken(payload)
+					assert.NilError(t, err)
+
+					token, err := parser.ParseToken(accessToken)
+					assert.NilError(t, err)
+					assert.DeepEqual(t, token.Payload, payload)
+
+					_, err = jwe.NewTokenParser(base64.RawURLEncoding, cs.encrypter, enc).ParseToken(accessToken)
+					assert.ErrorIs(t, err, jwe.ErrDecryptionFailed)
+				})
+			}
+		}
+	}
+}
+
+func TestAESKWCreateParse(t *testing.T) {
+	kek := fromHex("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	payload := jwt.Payload{"sub": "partner"}
+
+	cases := []jwe.KeyManager{
+		jwe.NewA128KW(kek[:16]),
+		jwe.NewA256KW(kek),
+	}
+
+	for _, keyManager := range cases {
+		creator := jwe.NewTokenCreator(base64.RawURLEncoding, keyManager, jwe.NewA256GCM())
+		parser := jwe.NewTokenParser(base64.RawURLEncoding, keyManager, jwe.NewA256GCM())
+
+		accessToken, err := creator.CreateToken(payload)
+		assert.NilError(t, err, keyManager.Alg())
+
+		token, err := parser.ParseToken(accessToken)
+		assert.NilError(t, err, keyManager.Alg())
+		assert.DeepEqual(t, token.Payload, payload)
+	}
+}
+
+func TestECDHWrongCurve(t *testing.T) {
+	private256, _ := loadECKeys(t, "ec256")
+	_, public384 := loadECKeys(t, "ec384")
+
+	creator := jwe.NewTokenCreator(base64.RawURLEncoding, jwe.NewECDHESEncrypter(public384), jwe.NewA128GCM())
+	parser := jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewECDHES(private256), jwe.NewA128GCM())
+
+	accessToken, err := creator.CreateToken(jwt.Payload{"sub": "partner"})
+	assert.NilError(t, err)
+
+	_, err = parser.ParseToken(accessToken)
+	assert.ErrorIs(t, err, jwe.ErrDecryptionFailed)
+}
@@ -0,0 +1,129 @@
+package jwe
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	"errors"
+)
+
+var (
+	ErrMissingPrivateKey = errors.New("private key required for decryption")
+	ErrInvalidKeySize    = errors.New("invalid key size")
+)
+
+// KeyManager determines content encryption key of the token, see RFC 7518 section 4.
+type KeyManager interface {
+	Alg() string
+	// EncryptKey returns content encryption key of cekSize bytes and its encrypted form,
+	// it could add key management parameters to the header.
+	EncryptKey(header *Header, cekSize int) (cek, encryptedKey []byte, err error)
+	DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error)
+}
+
+type RSAES struct {
+	public  *rsa.PublicKey
+	private *rsa.PrivateKey
+	hash    crypto.Hash
+}
+
+func (r *RSAES) EncryptKey(_ *Header, cekSize int) ([]byte, []byte, error) {
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(r.hash.New(), rand.Reader, r.public, cek, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (r *RSAES) DecryptKey(_ Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if r.private == nil {
+		return nil, ErrMissingPrivateKey
+	}
+
+	cek, err := rsa.DecryptOAEP(r.hash.New(), nil, r.private, encryptedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(cek) != cekSize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return cek, nil
+}
+
+type RSAOAEP struct{ *RSAES }
+
+func NewRSAOAEP(private *rsa.PrivateKey) *RSAOAEP {
+	return &RSAOAEP{RSAES: &RSAES{public: &private.PublicKey, private: private, hash: crypto.SHA1}}
+}
+
+// NewRSAOAEPEncrypter returns RSA-OAEP key manager that can only encrypt.
+func NewRSAOAEPEncrypter(public *rsa.PublicKey) *RSAOAEP {
+	return &RSAOAEP{RSAES: &RSAES{public: public, hash: crypto.SHA1}}
+}
+
+func (r *RSAOAEP) Alg() string {
+	return "RSA-OAEP"
+}
+
+type RSAOAEP256 struct{ *RSAES }
+
+func NewRSAOAEP256(private *rsa.PrivateKey) *RSAOAEP256 {
+	return &RSAOAEP256{RSAES: &RSAES{public: &private.PublicKey, private: private, hash: crypto.SHA256}}
+}
+
+// NewRSAOAEP256Encrypter returns RSA-OAEP-256 key manager that can only encrypt.
+func NewRSAOAEP256Encrypter(public *rsa.PublicKey) *RSAOAEP256 {
+	return &RSAOAEP256{RSAES: &RSAES{public: public, hash: crypto.SHA256}}
+}
+
+func (r *RSAOAEP256) Alg() string {
+	return "RSA-OAEP-256"
+}
+
+// Direct uses shared symmetric key as content encryption key.
+type Direct struct {
+	key []byte
+}
+
+func NewDirect(key []byte) *Direct {
+	return &Direct{key: key}
+}
+
+func (d *Direct) Alg() string {
+	return "dir"
+}
+
+func (d *Direct) EncryptKey(_ *Header, cekSize int) ([]byte, []byte, error) {
+	if len(d.key) != cekSize {
+		return nil, nil, ErrInvalidKeySize
+	}
+	return d.key, nil, nil
+}
+
+func (d *Direct) DecryptKey(_ Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if len(encryptedKey) != 0 {
+		return nil, ErrDecryptionFailed
+	}
+	if len(d.key) != cekSize {
+		return nil, ErrInvalidKeySize
+	}
+	return d.key, nil
+}
+
+func randomBytes(size int) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
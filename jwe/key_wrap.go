@@ -0,0 +1,139 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrKeyUnwrapFailed = errors.New("key unwrap failed")
+
+var keyWrapDefaultIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// AESKW wraps random content encryption key with shared key, see RFC 7518 section 4.4.
+type AESKW struct {
+	kek     []byte
+	keySize int
+}
+
+func (a *AESKW) EncryptKey(_ *Header, cekSize int) ([]byte, []byte, error) {
+	if len(a.kek) != a.keySize {
+		return nil, nil, ErrInvalidKeySize
+	}
+
+	cek, err := randomBytes(cekSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := wrapKey(a.kek, cek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+func (a *AESKW) DecryptKey(_ Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if len(a.kek) != a.keySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	cek, err := unwrapKey(a.kek, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(cek) != cekSize {
+		return nil, ErrInvalidKeySize
+	}
+
+	return cek, nil
+}
+
+type A128KW struct{ *AESKW }
+
+func NewA128KW(kek []byte) *A128KW {
+	return &A128KW{AESKW: &AESKW{kek: kek, keySize: 16}}
+}
+
+func (a *A128KW) Alg() string {
+	return "A128KW"
+}
+
+type A256KW struct{ *AESKW }
+
+func NewA256KW(kek []byte) *A256KW {
+	return &A256KW{AESKW: &AESKW{kek: kek, keySize: 32}}
+}
+
+func (a *A256KW) Alg() string {
+	return "A256KW"
+}
+
+// wrapKey implements AES Key Wrap of RFC 3394 section 2.2.1.
+func wrapKey(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, ErrInvalidKeySize
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(key) / 8
+	wrapped := make([]byte, 8+len(key))
+	copy(wrapped, keyWrapDefaultIV)
+	copy(wrapped[8:], key)
+
+	buffer := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buffer, wrapped[:8])
+			copy(buffer[8:], wrapped[i*8:i*8+8])
+			block.Encrypt(buffer, buffer)
+
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(wrapped[:8], binary.BigEndian.Uint64(buffer[:8])^t)
+			copy(wrapped[i*8:i*8+8], buffer[8:])
+		}
+	}
+
+	return wrapped, nil
+}
+
+// unwrapKey implements AES Key Unwrap of RFC 3394 section 2.2.2.
+func unwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrKeyUnwrapFailed
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	key := make([]byte, len(wrapped))
+	copy(key, wrapped)
+
+	buffer := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buffer[:8], binary.BigEndian.Uint64(key[:8])^t)
+			copy(buffer[8:], key[i*8:i*8+8])
+			block.Decrypt(buffer, buffer)
+
+			copy(key[:8], buffer[:8])
+			copy(key[i*8:i*8+8], buffer[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(key[:8], keyWrapDefaultIV) != 1 {
+		return nil, ErrKeyUnwrapFailed
+	}
+
+	return key[8:], nil
+}
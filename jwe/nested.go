@@ -0,0 +1,83 @@
+package jwe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+var ErrNotNestedToken jwt.TokenInvalidError = "not_nested_token"
+
+type Layer string
+
+const (
+	LayerEncryption Layer = "encryption"
+	LayerSignature  Layer = "signature"
+)
+
+// NestedTokenError reports which layer of nested token failed.
+type NestedTokenError struct {
+	Layer Layer
+	Err   error
+}
+
+func (e *NestedTokenError) Error() string {
+	return fmt.Sprintf("nested token %s layer failed, %s", e.Layer, e.Err)
+}
+
+func (e *NestedTokenError) Unwrap() error {
+	return e.Err
+}
+
+// NestedTokenCreator signs payload and encrypts the signed token, see RFC 7519 section 5.2.
+type NestedTokenCreator struct {
+	signer    jwt.TokenCreator
+	encrypter *TokenCreator
+}
+
+func NewNestedTokenCreator(signer jwt.TokenCreator, encrypter *TokenCreator) *NestedTokenCreator {
+	return &NestedTokenCreator{signer: signer, encrypter: encrypter}
+}
+
+func (c *NestedTokenCreator) CreateToken(payload jwt.Payload) (string, error) {
+	signedToken, err := c.signer.CreateToken(payload)
+	if err != nil {
+		return "", &NestedTokenError{Layer: LayerSignature, Err: err}
+	}
+
+	token, err := c.encrypter.Encrypt(Header{Cty: "JWT"}, []byte(signedToken))
+	if err != nil {
+		return "", &NestedTokenError{Layer: LayerEncryption, Err: err}
+	}
+
+	return token, nil
+}
+
+// NestedTokenParser decrypts nested token and verifies the inner signed token.
+type NestedTokenParser struct {
+	decrypter *TokenParser
+	verifier  jwt.TokenParser
+}
+
+func NewNestedTokenParser(decrypter *TokenParser, verifier jwt.TokenParser) *NestedTokenParser {
+	return &NestedTokenParser{decrypter: decrypter, verifier: verifier}
+}
+
+// ParseToken returns the inner token, errors are wrapped into NestedTokenError.
+func (p *NestedTokenParser) ParseToken(accessToken string) (jwt.Token, error) {
+	header, plaintext, err := p.decrypter.Decrypt(accessToken)
+	if err != nil {
+		return jwt.Token{}, &NestedTokenError{Layer: LayerEncryption, Err: err}
+	}
+	if !strings.EqualFold(header.Cty, "JWT") {
+		return jwt.Token{}, &NestedTokenError{Layer: LayerEncryption, Err: ErrNotNestedToken}
+	}
+
+	token, err := p.verifier.ParseToken(string(plaintext))
+	if err != nil {
+		return jwt.Token{}, &NestedTokenError{Layer: LayerSignature, Err: err}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,99 @@
+package jwe_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwe"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func TestNestedToken(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	otherPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+
+	signingMethod := signingmethods.NewHS256("nested secret")
+	encrypter := jwe.NewTokenCreator(base64.RawURLEncoding, jwe.NewRSAOAEP256Encrypter(&private.PublicKey), jwe.NewA256GCM())
+	decrypter := jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewRSAOAEP256(private), jwe.NewA256GCM())
+
+	creator := jwe.NewNestedTokenCreator(jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod), encrypter)
+	payload := jwt.Payload{"sub": "user", "ssn": "000-00-0000"}
+
+	nestedToken, err := creator.CreateToken(payload)
+	assert.NilError(t, err)
+
+	header, plaintext, err := decrypter.Decrypt(nestedToken)
+	assert.NilError(t, err)
+	assert.Equal(t, header.Cty, "JWT")
+	_, err = jwt.ParseRawToken(string(plaintext))
+	assert.NilError(t, err)
+
+	notNestedToken, err := encrypter.CreateToken(payload)
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name          string
+		parser        *jwe.NestedTokenParser
+		token         string
+		expectedToken jwt.Token
+		expectedLayer jwe.Layer
+		expectedErr   error
+	}{
+		{
+			name:          "success",
+			parser:        jwe.NewNestedTokenParser(decrypter, jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)),
+			token:         nestedToken,
+			expectedToken: jwt.NewToken(jwt.Header{Type: "JWT", Alg: "HS256"}, payload),
+		},
+		{
+			name: "wrong decryption key",
+			parser: jwe.NewNestedTokenParser(
+				jwe.NewTokenParser(base64.RawURLEncoding, jwe.NewRSAOAEP256(otherPrivate), jwe.NewA256GCM()),
+				jwt.NewTokenParser(base64.RawURLEncoding, signingMethod),
+			),
+			token:         nestedToken,
+			expectedLayer: jwe.LayerEncryption,
+			expectedErr:   jwe.ErrDecryptionFailed,
+		},
+		{
+			name:          "not nested token",
+			parser:        jwe.NewNestedTokenParser(decrypter, jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)),
+			token:         notNestedToken,
+			expectedLayer: jwe.LayerEncryption,
+			expectedErr:   jwe.ErrNotNestedToken,
+		},
+		{
+			name:          "wrong signature",
+			parser:        jwe.NewNestedTokenParser(decrypter, jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewHS256("other secret"))),
+			token:         nestedToken,
+			expectedLayer: jwe.LayerSignature,
+			expectedErr:   signingmethods.ErrSignatureInvalid,
+		},
+		{
+			name:          "wrong algoritm",
+			parser:        jwe.NewNestedTokenParser(decrypter, jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewHS512("nested secret"))),
+			token:         nestedToken,
+			expectedLayer: jwe.LayerSignature,
+			expectedErr:   jwt.ErrWrongAlgoritm,
+		},
+	}
+
+	for _, cs := range cases {
+		token, err := cs.parser.ParseToken(cs.token)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		assert.DeepEqual(t, token, cs.expectedToken)
+
+		var nestedErr *jwe.NestedTokenError
+		if cs.expectedErr != nil {
+			assert.Assert(t, errors.As(err, &nestedErr), cs.name)
+			assert.Equal(t, nestedErr.Layer, cs.expectedLayer, cs.name)
+		}
+	}
+}
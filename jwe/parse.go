@@ -0,0 +1,158 @@
+package jwe
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+type RawToken [5]string
+
+func (r RawToken) Header() string {
+	return r[0]
+}
+
+func (r RawToken) EncryptedKey() string {
+	return r[1]
+}
+
+func (r RawToken) IV() string {
+	return r[2]
+}
+
+func (r RawToken) Ciphertext() string {
+	return r[3]
+}
+
+func (r RawToken) Tag() string {
+	return r[4]
+}
+
+func ParseRawToken(token string) (RawToken, error) {
+	rawToken := strings.Split(token, ".")
+	if len(rawToken) != 5 {
+		return RawToken{}, jwt.ErrBadToken
+	}
+	return RawToken(rawToken), nil
+}
+
+// TokenParser decrypts JWE tokens in compact serialization, see RFC 7516 section 5.2.
+type TokenParser struct {
+	decoder           jwt.Decoder
+	keyManager        KeyManager
+	contentEncryption ContentEncryption
+}
+
+func NewTokenParser(decoder jwt.Decoder, keyManager KeyManager, contentEncryption ContentEncryption) *TokenParser {
+	return &TokenParser{
+		decoder:           decoder,
+		keyManager:        keyManager,
+		contentEncryption: contentEncryption,
+	}
+}
+
+func (p *TokenParser) ParseToken(token string) (jwt.Token, error) {
+	header, plaintext, err := p.Decrypt(token)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+
+	var payload jwt.Payload
+	err = json.Unmarshal(plaintext, &payload)
+	if err != nil {
+		return jwt.Token{}, fmt.Errorf("failed set payload, %w", jwt.ErrUnmarshalToken)
+	}
+
+	return jwt.NewToken(jwt.Header{Type: header.Typ, Alg: header.Alg}, payload), nil
+}
+
+// Decrypt returns protected header and decrypted plaintext of the token.
+func (p *TokenParser) Decrypt(token string) (Header, []byte, error) {
+	rawToken, err := ParseRawToken(token)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	header, err := p.DecodeHeader(rawToken)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	err = p.VerifyHeader(header)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	plaintext, err := p.DecryptContent(header, rawToken)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	return header, plaintext, nil
+}
+
+func (p *TokenParser) DecodeHeader(rawToken RawToken) (header Header, err error) {
+	rawHeader, err := p.decoder.DecodeString(rawToken.Header())
+	if err != nil {
+		return header, decodeHeaderError(jwt.ErrBadToken, err)
+	}
+	err = json.Unmarshal(rawHeader, &header)
+	if err != nil {
+		return header, decodeHeaderError(jwt.ErrUnmarshalToken, err)
+	}
+	return header, nil
+}
+
+func decodeHeaderError(code jwt.TokenInvalidError, err error) error {
+	return &jwt.ParseError{Stage: jwt.StageDecodeHeader, Segment: jwt.HeaderSegment, Err: fmt.Errorf("%w: %w", code, err)}
+}
+
+func (p *TokenParser) VerifyHeader(header Header) error {
+	if p.keyManager.Alg() != header.Alg {
+		return jwt.ErrWrongAlgoritm
+	}
+	if p.contentEncryption.Enc() != header.Enc {
+		return ErrWrongEncryption
+	}
+	if header.Zip != "" {
+		return ErrUnsupportedHeader
+	}
+	// no extensions are understood, so crit could not be processed, see RFC 7516 section 4.1.13
+	if header.Crit != nil {
+		return jwt.ErrUnsupportedCrit
+	}
+	return nil
+}
+
+func (p *TokenParser) DecryptContent(header Header, rawToken RawToken) ([]byte, error) {
+	encryptedKey, err := p.decoder.DecodeString(rawToken.EncryptedKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed decode encrypted key, %w", err)
+	}
+	iv, err := p.decoder.DecodeString(rawToken.IV())
+	if err != nil {
+		return nil, fmt.Errorf("failed decode iv, %w", err)
+	}
+	ciphertext, err := p.decoder.DecodeString(rawToken.Ciphertext())
+	if err != nil {
+		return nil, fmt.Errorf("failed decode ciphertext, %w", err)
+	}
+	tag, err := p.decoder.DecodeString(rawToken.Tag())
+	if err != nil {
+		return nil, fmt.Errorf("failed decode tag, %w", err)
+	}
+
+	cek, err := p.keyManager.DecryptKey(header, encryptedKey, p.contentEncryption.KeySize())
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypt key, %w", ErrDecryptionFailed)
+	}
+
+	plaintext, err := p.contentEncryption.Decrypt(cek, iv, ciphertext, tag, []byte(rawToken.Header()))
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypt content, %w", ErrDecryptionFailed)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,215 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrUnsupportedKeyType = errors.New("unsupported key type")
+	ErrUnsupportedCurve   = errors.New("unsupported curve")
+	ErrInvalidKey         = errors.New("invalid key")
+)
+
+// Key is JSON Web Key, see RFC 7517 section 4.
+type Key struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	// private members
+	D  string `json:"d,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+	K  string `json:"k,omitempty"`
+}
+
+// NewPublicKey returns JWK of RSA, EC or Ed25519 public key.
+func NewPublicKey(public crypto.PublicKey) (Key, error) {
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		return NewRSAPublicKey(public), nil
+	case *ecdsa.PublicKey:
+		return NewECPublicKey(public)
+	case ed25519.PublicKey:
+		return NewOKPPublicKey(public), nil
+	default:
+		return Key{}, ErrUnsupportedKeyType
+	}
+}
+
+// PublicKey returns *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey of the key.
+func (k Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		return k.RSAPublicKey()
+	case "EC":
+		return k.ECPublicKey()
+	case "OKP":
+		return k.Ed25519PublicKey()
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+}
+
+// Public returns copy of the key without private members.
+func (k Key) Public() Key {
+	k.D, k.P, k.Q, k.Dp, k.Dq, k.Qi, k.K = "", "", "", "", "", "", ""
+	return k
+}
+
+// Thumbprint returns hash of the key required members, see RFC 7638.
+func (k Key) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	var members any
+	switch k.Kty {
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	case "oct":
+		members = struct {
+			K   string `json:"k"`
+			Kty string `json:"kty"`
+		}{k.K, k.Kty}
+	default:
+		return nil, ErrUnsupportedKeyType
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// NewECPublicKey returns JWK of EC public key, coordinates are padded to curve size.
+func NewECPublicKey(public *ecdsa.PublicKey) (Key, error) {
+	crv, err := curveName(public.Curve)
+	if err != nil {
+		return Key{}, err
+	}
+
+	size := curveSize(public.Curve)
+
+	return Key{
+		Kty: "EC",
+		Crv: crv,
+		X:   encodeFixed(public.X, size),
+		Y:   encodeFixed(public.Y, size),
+	}, nil
+}
+
+// NewECPrivateKey returns JWK of EC private key.
+func NewECPrivateKey(private *ecdsa.PrivateKey) (Key, error) {
+	key, err := NewECPublicKey(&private.PublicKey)
+	if err != nil {
+		return Key{}, err
+	}
+	key.D = encodeFixed(private.D, curveSize(private.Curve))
+	return key, nil
+}
+
+func (k Key) ECPublicKey() (*ecdsa.PublicKey, error) {
+	if k.Kty != "EC" {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	curve, err := curveByName(k.Crv)
+	if err != nil {
+		return nil, err
+	}
+
+	size := curveSize(curve)
+	x, err := decodeFixed(k.X, size)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeFixed(k.Y, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if !curve.IsOnCurve(x, y) {
+		return nil, ErrInvalidKey
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func curveName(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "P-256", nil
+	case elliptic.P384():
+		return "P-384", nil
+	case elliptic.P521():
+		return "P-521", nil
+	default:
+		return "", ErrUnsupportedCurve
+	}
+}
+
+func curveByName(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, ErrUnsupportedCurve
+	}
+}
+
+func curveSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+func encodeFixed(value *big.Int, size int) string {
+	return base64.RawURLEncoding.EncodeToString(value.FillBytes(make([]byte, size)))
+}
+
+func decodeFixed(value string, size int) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(data), nil
+}
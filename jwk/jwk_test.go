@@ -0,0 +1,179 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"testing"
+
+	"github.com/amidgo/jwt/jwk"
+	"gotest.tools/v3/assert"
+)
+
+func TestECPublicKey(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		private, err := ecdsa.GenerateKey(curve, rand.Reader)
+		assert.NilError(t, err)
+
+		key, err := jwk.NewECPublicKey(&private.PublicKey)
+		assert.NilError(t, err)
+		assert.Equal(t, key.Crv, curve.Params().Name)
+
+		public, err := key.ECPublicKey()
+		assert.NilError(t, err)
+		assert.Assert(t, public.Equal(&private.PublicKey))
+	}
+}
+
+func TestECPublicKeyInvalid(t *testing.T) {
+	cases := []struct {
+		key         jwk.Key
+		expectedErr error
+	}{
+		{
+			key:         jwk.Key{Kty: "RSA"},
+			expectedErr: jwk.ErrUnsupportedKeyType,
+		},
+		{
+			key:         jwk.Key{Kty: "EC", Crv: "P-192"},
+			expectedErr: jwk.ErrUnsupportedCurve,
+		},
+		{
+			key: jwk.Key{
+				Kty: "EC",
+				Crv: "P-256",
+				X:   "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+				Y:   "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+			},
+			expectedErr: jwk.ErrInvalidKey,
+		},
+		{
+			key: jwk.Key{
+				Kty: "EC",
+				Crv: "P-256",
+				X:   "gI0GAILBdu7T53akrFmMyGcsF3n5dO7M",
+				Y:   "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+			},
+			expectedErr: jwk.ErrInvalidKey,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := cs.key.ECPublicKey()
+		assert.ErrorIs(t, err, cs.expectedErr)
+	}
+}
+
+// RFC 7520 section 3 keys, thumbprints are computed as RFC 7638 defines.
+func TestThumbprint(t *testing.T) {
+	cases := []struct {
+		key                jwk.Key
+		expectedThumbprint string
+	}{
+		{
+			key: jwk.Key{
+				Kty: "EC",
+				Kid: "bilbo.baggins@hobbiton.example",
+				Use: "sig",
+				Crv: "P-521",
+				X:   "AHKZLLOsCOzz5cY97ewNUajB957y-C-U88c3v13nmGZx6sYl_oJXu9A5RkTKqjqvjyekWF-7ytDyRXYgCF5cj0Kt",
+				Y:   "AdymlHvOiLxXkEhayXQnNCvDX4h9htZaCJN34kfmC6pV5OhQHiraVySsUdaQkAgDPrwQrJmbnX9cwlGfP-HqHZR1",
+			},
+			expectedThumbprint: "747ae2dd2003664aeeb21e4753fe7402846170a16bc8df8f23a8cf06d3cbe793",
+		},
+		{
+			key: jwk.Key{
+				Kty: "RSA",
+				Kid: "bilbo.baggins@hobbiton.example",
+				Use: "sig",
+				N:   "n4EPtAOCc9AlkeQHPzHStgAbgs7bTZLwUBZdR8_KuKPEHLd4rHVTeT-O-XV2jRojdNhxJWTDvNd7nqQ0VEiZQHz_AJmSCpMaJMRBSFKrKb2wqVwGU_NsYOYL-QtiWN2lbzcEe6XC0dApr5ydQLrHqkHHig3RBordaZ6Aj-oBHqFEHYpPe7Tpe-OfVfHd1E6cS6M1FZcD1NNLYD5lFHpPI9bTwJlsde3uhGqC0ZCuEHg8lhzwOHrtIQbS0FVbb9k3-tVTU4fg_3L_vniUFAKwuCLqKnS2BYwdq_mzSnbLY7h_qixoR7jig3__kRhuaxwUkRz5iaiQkqgc5gHdrNP5zw",
+				E:   "AQAB",
+			},
+			expectedThumbprint: "f63838e96077ad1fc01c3f8405774dedc0641f558ebb4b40dccf5f9b6d66a932",
+		},
+		{
+			key: jwk.Key{
+				Kty: "OKP",
+				Crv: "Ed25519",
+				X:   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+				D:   "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+			},
+			expectedThumbprint: "90facafea9b1556698540f70c0117a22ea37bd5cf3ed3c47093c1707282b4b89",
+		},
+	}
+
+	for _, cs := range cases {
+		thumbprint, err := cs.key.Thumbprint(crypto.SHA256)
+		assert.NilError(t, err)
+		assert.Equal(t, hex.EncodeToString(thumbprint), cs.expectedThumbprint)
+
+		thumbprint, err = cs.key.Public().Thumbprint(crypto.SHA256)
+		assert.NilError(t, err)
+		assert.Equal(t, hex.EncodeToString(thumbprint), cs.expectedThumbprint)
+	}
+
+	_, err := jwk.Key{Kty: "XXX"}.Thumbprint(crypto.SHA256)
+	assert.ErrorIs(t, err, jwk.ErrUnsupportedKeyType)
+}
+
+func TestPublicKey(t *testing.T) {
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NilError(t, err)
+
+	privateKeys := []jwk.Key{jwk.NewRSAPrivateKey(rsaPrivate), jwk.NewOKPPrivateKey(edPrivate)}
+	ecKey, err := jwk.NewECPrivateKey(ecPrivate)
+	assert.NilError(t, err)
+	privateKeys = append(privateKeys, ecKey)
+
+	for _, key := range privateKeys {
+		assert.Assert(t, key.D != "")
+		assert.Equal(t, key.Public().D, "")
+	}
+
+	for _, public := range []crypto.PublicKey{&rsaPrivate.PublicKey, &ecPrivate.PublicKey, edPublic} {
+		key, err := jwk.NewPublicKey(public)
+		assert.NilError(t, err)
+
+		parsed, err := key.PublicKey()
+		assert.NilError(t, err)
+		assert.Assert(t, parsed.(interface{ Equal(crypto.PublicKey) bool }).Equal(public))
+	}
+
+	_, err = jwk.NewPublicKey(&ecPrivate)
+	assert.ErrorIs(t, err, jwk.ErrUnsupportedKeyType)
+	_, err = jwk.Key{Kty: "RSA", N: "AQAB"}.RSAPublicKey()
+	assert.ErrorIs(t, err, jwk.ErrInvalidKey)
+	_, err = jwk.Key{Kty: "OKP", Crv: "X25519"}.Ed25519PublicKey()
+	assert.ErrorIs(t, err, jwk.ErrUnsupportedCurve)
+}
+
+func TestSymmetricKey(t *testing.T) {
+	key := jwk.NewSymmetricKey([]byte("secret"))
+	assert.Equal(t, key.Kty, "oct")
+
+	secret, err := key.SymmetricKey()
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret), "secret")
+
+	_, err = key.PublicKey()
+	assert.ErrorIs(t, err, jwk.ErrUnsupportedKeyType)
+}
+
+func TestSet(t *testing.T) {
+	set := jwk.Set{Keys: []jwk.Key{{Kty: "oct", Kid: "first"}, {Kty: "EC", Kid: "second"}}}
+
+	key, ok := set.Key("second")
+	assert.Assert(t, ok)
+	assert.Equal(t, key.Kty, "EC")
+
+	_, ok = set.Key("third")
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,27 @@
+package jwk
+
+import "encoding/base64"
+
+// NewSymmetricKey returns JWK of symmetric key, e.g. HMAC secret or AES key wrap key.
+func NewSymmetricKey(secret []byte) Key {
+	return Key{
+		Kty: "oct",
+		K:   base64.RawURLEncoding.EncodeToString(secret),
+	}
+}
+
+func (k Key) SymmetricKey() ([]byte, error) {
+	if k.Kty != "oct" {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(k.K)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	return data, nil
+}
@@ -0,0 +1,40 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+)
+
+// NewOKPPublicKey returns JWK of Ed25519 public key, see RFC 8037.
+func NewOKPPublicKey(public ed25519.PublicKey) Key {
+	return Key{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(public),
+	}
+}
+
+func NewOKPPrivateKey(private ed25519.PrivateKey) Key {
+	key := NewOKPPublicKey(private.Public().(ed25519.PublicKey))
+	key.D = base64.RawURLEncoding.EncodeToString(private.Seed())
+	return key
+}
+
+func (k Key) Ed25519PublicKey() (ed25519.PublicKey, error) {
+	if k.Kty != "OKP" {
+		return nil, ErrUnsupportedKeyType
+	}
+	if k.Crv != "Ed25519" {
+		return nil, ErrUnsupportedCurve
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519.PublicKey(data), nil
+}
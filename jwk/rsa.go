@@ -0,0 +1,67 @@
+package jwk
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+func NewRSAPublicKey(public *rsa.PublicKey) Key {
+	return Key{
+		Kty: "RSA",
+		N:   encodeBig(public.N),
+		E:   encodeBig(big.NewInt(int64(public.E))),
+	}
+}
+
+// NewRSAPrivateKey returns JWK of RSA private key, CRT members are set only for two prime keys.
+func NewRSAPrivateKey(private *rsa.PrivateKey) Key {
+	key := NewRSAPublicKey(&private.PublicKey)
+	key.D = encodeBig(private.D)
+	if len(private.Primes) != 2 {
+		return key
+	}
+
+	private.Precompute()
+	key.P = encodeBig(private.Primes[0])
+	key.Q = encodeBig(private.Primes[1])
+	key.Dp = encodeBig(private.Precomputed.Dp)
+	key.Dq = encodeBig(private.Precomputed.Dq)
+	key.Qi = encodeBig(private.Precomputed.Qinv)
+	return key
+}
+
+func (k Key) RSAPublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	n, err := decodeBig(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBig(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if n.Sign() <= 0 || !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, ErrInvalidKey
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func encodeBig(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func decodeBig(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,16 @@
+package jwk
+
+// Set is JWK Set, see RFC 7517 section 5.
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// Key returns the first key with the kid.
+func (s Set) Key(kid string) (Key, bool) {
+	for _, key := range s.Keys {
+		if key.Kid == kid {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
@@ -0,0 +1,49 @@
+package jwttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/amidgo/jwt/jwk"
+)
+
+// KeyID returns kid of the issuer key, it is base64url SHA-256 JWK thumbprint.
+func (i *Issuer) KeyID() string {
+	return i.kid
+}
+
+// JWKS returns JWK Set with the issuer public key.
+func (i *Issuer) JWKS() jwk.Set {
+	i.t.Helper()
+
+	key, err := jwk.NewECPublicKey(&i.private.PublicKey)
+	if err != nil {
+		i.t.Fatalf("jwttest: failed create jwk, %s", err)
+	}
+	key.Kid = i.kid
+	key.Use = "sig"
+	key.Alg = "ES256"
+
+	return jwk.Set{Keys: []jwk.Key{key}}
+}
+
+// JWKSHandler serves the issuer JWK Set on every path.
+func (i *Issuer) JWKSHandler() http.Handler {
+	data, err := json.Marshal(i.JWKS())
+	if err != nil {
+		i.t.Fatalf("jwttest: failed marshal jwks, %s", err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+// ServeJWKS starts server of JWKSHandler, the server is closed on test cleanup.
+func (i *Issuer) ServeJWKS() *httptest.Server {
+	server := httptest.NewServer(i.JWKSHandler())
+	i.t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,182 @@
+// Package jwttest provides in-memory issuer of real signed tokens for tests of token consumers.
+package jwttest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+const DefaultTTL = time.Hour
+
+type IssuerOption func(i *Issuer)
+
+// WithIssuer sets iss claim of issued tokens.
+func WithIssuer(issuer string) IssuerOption {
+	return func(i *Issuer) {
+		i.issuer = issuer
+	}
+}
+
+// WithAudience sets aud claim of issued tokens.
+func WithAudience(audience ...string) IssuerOption {
+	return func(i *Issuer) {
+		i.audience = audience
+	}
+}
+
+// WithTTL sets lifetime of valid tokens, it is DefaultTTL by default.
+func WithTTL(ttl time.Duration) IssuerOption {
+	return func(i *Issuer) {
+		i.ttl = ttl
+	}
+}
+
+// WithClock sets clock used for iat, exp and nbf claims.
+func WithClock(clock jwt.Clock) IssuerOption {
+	return func(i *Issuer) {
+		i.clock = clock
+	}
+}
+
+// Issuer signs tokens with throwaway ES256 key generated for every issuer.
+// Registered claims are set only when they are absent from the payload, jti is random.
+// Tokens have kid header of the issuer key, see KeyID.
+// Any error fails the test.
+type Issuer struct {
+	t        testing.TB
+	private  *ecdsa.PrivateKey
+	kid      string
+	issuer   string
+	audience []string
+	ttl      time.Duration
+	clock    jwt.Clock
+}
+
+func NewIssuer(t testing.TB, opts ...IssuerOption) *Issuer {
+	t.Helper()
+
+	issuer := &Issuer{
+		t:       t,
+		private: generateKey(t),
+		ttl:     DefaultTTL,
+		clock:   jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(issuer)
+	}
+
+	key, err := jwk.NewECPublicKey(&issuer.private.PublicKey)
+	if err != nil {
+		t.Fatalf("jwttest: failed create jwk, %s", err)
+	}
+	thumbprint, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		t.Fatalf("jwttest: failed create key id, %s", err)
+	}
+	issuer.kid = base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	return issuer
+}
+
+func generateKey(t testing.TB) *ecdsa.PrivateKey {
+	t.Helper()
+
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("jwttest: failed generate key, %s", err)
+	}
+	return private
+}
+
+// SigningMethod returns signing method with the issuer private key.
+func (i *Issuer) SigningMethod() jwt.SigningMethod {
+	return signingmethods.NewES256(i.private)
+}
+
+func (i *Issuer) PublicKey() *ecdsa.PublicKey {
+	return &i.private.PublicKey
+}
+
+// Parser returns parser that verifies tokens of the issuer with its public key.
+func (i *Issuer) Parser() *jwt.JwtTokenParser {
+	return jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewES256Verifier(&i.private.PublicKey))
+}
+
+// CreateToken creates valid token, so Issuer is jwt.TokenCreator.
+func (i *Issuer) CreateToken(payload jwt.Payload) (string, error) {
+	return i.creator(i.SigningMethod(), i.clock, jwt.WithTTL(i.ttl)).CreateToken(payload)
+}
+
+// Token returns token valid for the issuer ttl.
+func (i *Issuer) Token(payload jwt.Payload) string {
+	i.t.Helper()
+
+	return i.mustCreate(i.creator(i.SigningMethod(), i.clock, jwt.WithTTL(i.ttl)), payload)
+}
+
+// ExpiredToken returns token expired the issuer ttl ago.
+func (i *Issuer) ExpiredToken(payload jwt.Payload) string {
+	i.t.Helper()
+
+	past := shiftedClock(i.clock, -2*i.ttl)
+	return i.mustCreate(i.creator(i.SigningMethod(), past, jwt.WithTTL(i.ttl)), payload)
+}
+
+// NotYetValidToken returns token with nbf in the issuer ttl from now, it expires in two ttl.
+func (i *Issuer) NotYetValidToken(payload jwt.Payload) string {
+	i.t.Helper()
+
+	return i.mustCreate(i.creator(i.SigningMethod(), i.clock, jwt.WithTTL(2*i.ttl), jwt.WithNotBefore(i.ttl)), payload)
+}
+
+// WrongSignatureToken returns valid token signed by another throwaway key with the same alg.
+func (i *Issuer) WrongSignatureToken(payload jwt.Payload) string {
+	i.t.Helper()
+
+	signingMethod := signingmethods.NewES256(generateKey(i.t))
+	return i.mustCreate(i.creator(signingMethod, i.clock, jwt.WithTTL(i.ttl)), payload)
+}
+
+// WrongAlgToken returns valid token signed with HS256 by the issuer key id as secret.
+func (i *Issuer) WrongAlgToken(payload jwt.Payload) string {
+	i.t.Helper()
+
+	signingMethod := signingmethods.NewHS256(i.kid)
+	return i.mustCreate(i.creator(signingMethod, i.clock, jwt.WithTTL(i.ttl)), payload)
+}
+
+func (i *Issuer) creator(signingMethod jwt.SigningMethod, clock jwt.Clock, opts ...jwt.CreatorOption) *jwt.JwtTokenCreator {
+	opts = append(opts, jwt.WithClock(clock), jwt.WithKeyID(i.kid))
+	if i.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(i.issuer))
+	}
+	if len(i.audience) != 0 {
+		opts = append(opts, jwt.WithAudience(i.audience...))
+	}
+	return jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, opts...)
+}
+
+func (i *Issuer) mustCreate(creator jwt.TokenCreator, payload jwt.Payload) string {
+	i.t.Helper()
+
+	token, err := creator.CreateToken(payload)
+	if err != nil {
+		i.t.Fatalf("jwttest: failed create token, %s", err)
+	}
+	return token
+}
+
+func shiftedClock(clock jwt.Clock, offset time.Duration) jwt.Clock {
+	return jwt.ClockFunc(func() time.Time {
+		return clock.Now().Add(offset)
+	})
+}
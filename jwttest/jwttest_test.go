@@ -0,0 +1,89 @@
+package jwttest_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func TestIssuerTokens(t *testing.T) {
+	issuer := jwttest.NewIssuer(t, jwttest.WithIssuer("https://issuer.example"), jwttest.WithAudience("api"))
+	parser := issuer.Parser()
+
+	token, err := parser.ParseToken(issuer.Token(jwt.Payload{"sub": "user"}))
+	assert.NilError(t, err)
+	assert.NilError(t, jwt.ValidateToken(token, jwt.VerifyTokenExpiration, jwt.VerifyTokenNotBefore))
+	assert.Equal(t, token.Header.Alg, "ES256")
+	assert.Equal(t, token.Header.KeyID, issuer.KeyID())
+	assert.Equal(t, token.Payload["sub"], "user")
+	assert.Equal(t, token.Payload["iss"], "https://issuer.example")
+	assert.Equal(t, token.Payload["aud"], "api")
+	assert.Assert(t, token.Payload["jti"] != nil)
+
+	token, err = parser.ParseToken(issuer.ExpiredToken(jwt.Payload{"sub": "user"}))
+	assert.NilError(t, err)
+	assert.ErrorIs(t, jwt.ValidateToken(token, jwt.VerifyTokenExpiration), jwt.ErrTokenExpired)
+
+	token, err = parser.ParseToken(issuer.NotYetValidToken(jwt.Payload{"sub": "user"}))
+	assert.NilError(t, err)
+	assert.ErrorIs(t, jwt.ValidateToken(token, jwt.VerifyTokenExpiration, jwt.VerifyTokenNotBefore), jwt.ErrTokenNotYetValid)
+	assert.Assert(t, int64(token.Payload["nbf"].(float64)) > time.Now().Unix())
+
+	_, err = parser.ParseToken(issuer.WrongSignatureToken(jwt.Payload{"sub": "user"}))
+	assert.ErrorIs(t, err, signingmethods.ErrSignatureInvalid)
+
+	_, err = parser.ParseToken(issuer.WrongAlgToken(jwt.Payload{"sub": "user"}))
+	assert.ErrorIs(t, err, jwt.ErrWrongAlgoritm)
+
+	_, err = jwttest.NewIssuer(t).Parser().ParseToken(issuer.Token(nil))
+	assert.ErrorIs(t, err, signingmethods.ErrSignatureInvalid)
+}
+
+func TestIssuerClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issuer := jwttest.NewIssuer(t, jwttest.WithClock(jwt.ClockFunc(func() time.Time { return now })), jwttest.WithTTL(time.Minute))
+
+	accessToken, err := issuer.CreateToken(jwt.Payload{"exp": 1})
+	assert.NilError(t, err)
+	token, err := issuer.Parser().ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Payload["exp"], float64(1))
+	assert.Equal(t, token.Payload["iat"], float64(now.Unix()))
+
+	token, err = issuer.Parser().ParseToken(issuer.ExpiredToken(nil))
+	assert.NilError(t, err)
+	assert.Equal(t, token.Payload["exp"], float64(now.Add(-time.Minute).Unix()))
+}
+
+func TestIssuerServeJWKS(t *testing.T) {
+	issuer := jwttest.NewIssuer(t)
+	server := issuer.ServeJWKS()
+
+	resp, err := http.Get(server.URL + "/.well-known/jwks.json")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+	var set jwk.Set
+	assert.NilError(t, json.NewDecoder(resp.Body).Decode(&set))
+
+	key, ok := set.Key(issuer.KeyID())
+	assert.Assert(t, ok)
+	assert.Equal(t, key.Alg, "ES256")
+
+	public, err := key.ECPublicKey()
+	assert.NilError(t, err)
+	assert.Assert(t, public.Equal(issuer.PublicKey()))
+
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewES256Verifier(public))
+	_, err = parser.ParseToken(issuer.Token(jwt.Payload{"sub": "user"}))
+	assert.NilError(t, err)
+}
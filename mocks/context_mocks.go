@@ -0,0 +1,192 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: context.go
+
+// Package jwtmocks is a generated GoMock package.
+package jwtmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jwt "github.com/amidgo/jwt"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockContextSigningMethod is a mock of ContextSigningMethod interface.
+type MockContextSigningMethod struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextSigningMethodMockRecorder
+}
+
+// MockContextSigningMethodMockRecorder is the mock recorder for MockContextSigningMethod.
+type MockContextSigningMethodMockRecorder struct {
+	mock *MockContextSigningMethod
+}
+
+// NewMockContextSigningMethod creates a new mock instance.
+func NewMockContextSigningMethod(ctrl *gomock.Controller) *MockContextSigningMethod {
+	mock := &MockContextSigningMethod{ctrl: ctrl}
+	mock.recorder = &MockContextSigningMethodMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextSigningMethod) EXPECT() *MockContextSigningMethodMockRecorder {
+	return m.recorder
+}
+
+// Alg mocks base method.
+func (m *MockContextSigningMethod) Alg() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Alg")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Alg indicates an expected call of Alg.
+func (mr *MockContextSigningMethodMockRecorder) Alg() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Alg", reflect.TypeOf((*MockContextSigningMethod)(nil).Alg))
+}
+
+// SignContext mocks base method.
+func (m *MockContextSigningMethod) SignContext(ctx context.Context, stringToSign string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignContext", ctx, stringToSign)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignContext indicates an expected call of SignContext.
+func (mr *MockContextSigningMethodMockRecorder) SignContext(ctx, stringToSign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignContext", reflect.TypeOf((*MockContextSigningMethod)(nil).SignContext), ctx, stringToSign)
+}
+
+// VerifyContext mocks base method.
+func (m *MockContextSigningMethod) VerifyContext(ctx context.Context, verifyData string, sign []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyContext", ctx, verifyData, sign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyContext indicates an expected call of VerifyContext.
+func (mr *MockContextSigningMethodMockRecorder) VerifyContext(ctx, verifyData, sign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyContext", reflect.TypeOf((*MockContextSigningMethod)(nil).VerifyContext), ctx, verifyData, sign)
+}
+
+// MockContextTokenParser is a mock of ContextTokenParser interface.
+type MockContextTokenParser struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextTokenParserMockRecorder
+}
+
+// MockContextTokenParserMockRecorder is the mock recorder for MockContextTokenParser.
+type MockContextTokenParserMockRecorder struct {
+	mock *MockContextTokenParser
+}
+
+// NewMockContextTokenParser creates a new mock instance.
+func NewMockContextTokenParser(ctrl *gomock.Controller) *MockContextTokenParser {
+	mock := &MockContextTokenParser{ctrl: ctrl}
+	mock.recorder = &MockContextTokenParserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextTokenParser) EXPECT() *MockContextTokenParserMockRecorder {
+	return m.recorder
+}
+
+// ParseTokenContext mocks base method.
+func (m *MockContextTokenParser) ParseTokenContext(ctx context.Context, accessToken string) (jwt.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseTokenContext", ctx, accessToken)
+	ret0, _ := ret[0].(jwt.Token)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseTokenContext indicates an expected call of ParseTokenContext.
+func (mr *MockContextTokenParserMockRecorder) ParseTokenContext(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseTokenContext", reflect.TypeOf((*MockContextTokenParser)(nil).ParseTokenContext), ctx, accessToken)
+}
+
+// MockContextTokenCreator is a mock of ContextTokenCreator interface.
+type MockContextTokenCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextTokenCreatorMockRecorder
+}
+
+// MockContextTokenCreatorMockRecorder is the mock recorder for MockContextTokenCreator.
+type MockContextTokenCreatorMockRecorder struct {
+	mock *MockContextTokenCreator
+}
+
+// NewMockContextTokenCreator creates a new mock instance.
+func NewMockContextTokenCreator(ctrl *gomock.Controller) *MockContextTokenCreator {
+	mock := &MockContextTokenCreator{ctrl: ctrl}
+	mock.recorder = &MockContextTokenCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextTokenCreator) EXPECT() *MockContextTokenCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateTokenContext mocks base method.
+func (m *MockContextTokenCreator) CreateTokenContext(ctx context.Context, payload jwt.Payload) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTokenContext", ctx, payload)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTokenContext indicates an expected call of CreateTokenContext.
+func (mr *MockContextTokenCreatorMockRecorder) CreateTokenContext(ctx, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTokenContext", reflect.TypeOf((*MockContextTokenCreator)(nil).CreateTokenContext), ctx, payload)
+}
+
+// MockContextTokenValidator is a mock of ContextTokenValidator interface.
+type MockContextTokenValidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextTokenValidatorMockRecorder
+}
+
+// MockContextTokenValidatorMockRecorder is the mock recorder for MockContextTokenValidator.
+type MockContextTokenValidatorMockRecorder struct {
+	mock *MockContextTokenValidator
+}
+
+// NewMockContextTokenValidator creates a new mock instance.
+func NewMockContextTokenValidator(ctrl *gomock.Controller) *MockContextTokenValidator {
+	mock := &MockContextTokenValidator{ctrl: ctrl}
+	mock.recorder = &MockContextTokenValidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextTokenValidator) EXPECT() *MockContextTokenValidatorMockRecorder {
+	return m.recorder
+}
+
+// ValidateTokenContext mocks base method.
+func (m *MockContextTokenValidator) ValidateTokenContext(ctx context.Context, token jwt.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateTokenContext", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateTokenContext indicates an expected call of ValidateTokenContext.
+func (mr *MockContextTokenValidatorMockRecorder) ValidateTokenContext(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateTokenContext", reflect.TypeOf((*MockContextTokenValidator)(nil).ValidateTokenContext), ctx, token)
+}
@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revocation.go
+
+// Package jwtmocks is a generated GoMock package.
+package jwtmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevocationStore is a mock of RevocationStore interface.
+type MockRevocationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationStoreMockRecorder
+}
+
+// MockRevocationStoreMockRecorder is the mock recorder for MockRevocationStore.
+type MockRevocationStoreMockRecorder struct {
+	mock *MockRevocationStore
+}
+
+// NewMockRevocationStore creates a new mock instance.
+func NewMockRevocationStore(ctrl *gomock.Controller) *MockRevocationStore {
+	mock := &MockRevocationStore{ctrl: ctrl}
+	mock.recorder = &MockRevocationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationStore) EXPECT() *MockRevocationStoreMockRecorder {
+	return m.recorder
+}
+
+// IsIDRevoked mocks base method.
+func (m *MockRevocationStore) IsIDRevoked(jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIDRevoked", jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsIDRevoked indicates an expected call of IsIDRevoked.
+func (mr *MockRevocationStoreMockRecorder) IsIDRevoked(jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIDRevoked", reflect.TypeOf((*MockRevocationStore)(nil).IsIDRevoked), jti)
+}
+
+// RevokeID mocks base method.
+func (m *MockRevocationStore) RevokeID(jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeID", jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeID indicates an expected call of RevokeID.
+func (mr *MockRevocationStoreMockRecorder) RevokeID(jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeID", reflect.TypeOf((*MockRevocationStore)(nil).RevokeID), jti, expiresAt)
+}
+
+// RevokeSubject mocks base method.
+func (m *MockRevocationStore) RevokeSubject(sub string, issuedBefore, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSubject", sub, issuedBefore, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSubject indicates an expected call of RevokeSubject.
+func (mr *MockRevocationStoreMockRecorder) RevokeSubject(sub, issuedBefore, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSubject", reflect.TypeOf((*MockRevocationStore)(nil).RevokeSubject), sub, issuedBefore, expiresAt)
+}
+
+// SubjectRevokedBefore mocks base method.
+func (m *MockRevocationStore) SubjectRevokedBefore(sub string) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubjectRevokedBefore", sub)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SubjectRevokedBefore indicates an expected call of SubjectRevokedBefore.
+func (mr *MockRevocationStoreMockRecorder) SubjectRevokedBefore(sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubjectRevokedBefore", reflect.TypeOf((*MockRevocationStore)(nil).SubjectRevokedBefore), sub)
+}
+
+// MockContextRevocationStore is a mock of ContextRevocationStore interface.
+type MockContextRevocationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextRevocationStoreMockRecorder
+}
+
+// MockContextRevocationStoreMockRecorder is the mock recorder for MockContextRevocationStore.
+type MockContextRevocationStoreMockRecorder struct {
+	mock *MockContextRevocationStore
+}
+
+// NewMockContextRevocationStore creates a new mock instance.
+func NewMockContextRevocationStore(ctrl *gomock.Controller) *MockContextRevocationStore {
+	mock := &MockContextRevocationStore{ctrl: ctrl}
+	mock.recorder = &MockContextRevocationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextRevocationStore) EXPECT() *MockContextRevocationStoreMockRecorder {
+	return m.recorder
+}
+
+// IsIDRevokedContext mocks base method.
+func (m *MockContextRevocationStore) IsIDRevokedContext(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIDRevokedContext", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsIDRevokedContext indicates an expected call of IsIDRevokedContext.
+func (mr *MockContextRevocationStoreMockRecorder) IsIDRevokedContext(ctx, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIDRevokedContext", reflect.TypeOf((*MockContextRevocationStore)(nil).IsIDRevokedContext), ctx, jti)
+}
+
+// SubjectRevokedBeforeContext mocks base method.
+func (m *MockContextRevocationStore) SubjectRevokedBeforeContext(ctx context.Context, sub string) (time.Time, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubjectRevokedBeforeContext", ctx, sub)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SubjectRevokedBeforeContext indicates an expected call of SubjectRevokedBeforeContext.
+func (mr *MockContextRevocationStoreMockRecorder) SubjectRevokedBeforeContext(ctx, sub interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubjectRevokedBeforeContext", reflect.TypeOf((*MockContextRevocationStore)(nil).SubjectRevokedBeforeContext), ctx, sub)
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token_pair.go
+
+// Package jwtmocks is a generated GoMock package.
+package jwtmocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRefreshTokenStore is a mock of RefreshTokenStore interface.
+type MockRefreshTokenStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenStoreMockRecorder
+}
+
+// MockRefreshTokenStoreMockRecorder is the mock recorder for MockRefreshTokenStore.
+type MockRefreshTokenStoreMockRecorder struct {
+	mock *MockRefreshTokenStore
+}
+
+// NewMockRefreshTokenStore creates a new mock instance.
+func NewMockRefreshTokenStore(ctrl *gomock.Controller) *MockRefreshTokenStore {
+	mock := &MockRefreshTokenStore{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenStore) EXPECT() *MockRefreshTokenStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateFamily mocks base method.
+func (m *MockRefreshTokenStore) CreateFamily(familyID, refreshID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFamily", familyID, refreshID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFamily indicates an expected call of CreateFamily.
+func (mr *MockRefreshTokenStoreMockRecorder) CreateFamily(familyID, refreshID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFamily", reflect.TypeOf((*MockRefreshTokenStore)(nil).CreateFamily), familyID, refreshID, expiresAt)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockRefreshTokenStore) RevokeFamily(familyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRefreshTokenStoreMockRecorder) RevokeFamily(familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRefreshTokenStore)(nil).RevokeFamily), familyID)
+}
+
+// Rotate mocks base method.
+func (m *MockRefreshTokenStore) Rotate(familyID, currentID, nextID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", familyID, currentID, nextID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockRefreshTokenStoreMockRecorder) Rotate(familyID, currentID, nextID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRefreshTokenStore)(nil).Rotate), familyID, currentID, nextID, expiresAt)
+}
@@ -0,0 +1,42 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var ErrIssuerMismatch = errors.New("issuer of provider metadata does not match")
+
+// ProviderMetadata is OpenID Provider configuration, see OpenID Connect Discovery 1.0 section 3.
+type ProviderMetadata struct {
+	Issuer                           string   `json:"issuer"`
+	AuthorizationEndpoint            string   `json:"authorization_endpoint"`
+	TokenEndpoint                    string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                 string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// Discover fetches provider metadata from issuer well-known configuration endpoint.
+// Issuer of the metadata must be equal to issuer, see OpenID Connect Discovery 1.0 section 4.3.
+func Discover(ctx context.Context, client *http.Client, issuer string) (ProviderMetadata, error) {
+	var metadata ProviderMetadata
+	err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &metadata)
+	if err != nil {
+		return ProviderMetadata{}, fmt.Errorf("failed fetch provider metadata, %w", err)
+	}
+	if metadata.Issuer != issuer {
+		return ProviderMetadata{}, ErrIssuerMismatch
+	}
+	return metadata, nil
+}
+
+// NewProviderVerifier returns Verifier of ID tokens issued by provider, keys are fetched from provider jwks_uri.
+// Algorithms are limited to id_token_signing_alg_values_supported of the provider.
+func NewProviderVerifier(client *http.Client, metadata ProviderMetadata, clientID string, opts ...VerifierOption) *Verifier {
+	opts = append([]VerifierOption{WithAlgorithms(metadata.IDTokenSigningAlgValuesSupported...)}, opts...)
+	return NewVerifier(metadata.Issuer, clientID, NewRemoteKeySet(client, metadata.JWKSURI), opts...)
+}
@@ -0,0 +1,184 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+)
+
+// KeySet returns verification keys of the provider.
+type KeySet interface {
+	// Keys returns keys with kid, every key is returned for empty kid.
+	Keys(ctx context.Context, kid string) ([]jwk.Key, error)
+}
+
+// StaticKeySet is KeySet of fixed JWK Set.
+type StaticKeySet struct {
+	set jwk.Set
+}
+
+func NewStaticKeySet(set jwk.Set) *StaticKeySet {
+	return &StaticKeySet{set: set}
+}
+
+func (s *StaticKeySet) Keys(ctx context.Context, kid string) ([]jwk.Key, error) {
+	return matchKeys(s.set, kid), nil
+}
+
+// minRefreshInterval limits refetches of remote JWK Set caused by tokens with unknown kid, failed fetches are limited too.
+const minRefreshInterval = time.Minute
+
+// RemoteKeySet is KeySet that fetches JWK Set from jwks_uri, safe for concurrent use.
+// The set is fetched on first use and refetched when token has unknown kid, e.g. after key rotation,
+// but not more often than once a minute.
+// Concurrent calls share single fetch, the fetch is not cancelled with ctx of the call, so client should have timeout.
+// Generation changes when fetched set differs from the previous one, see jwt.WithCacheKeyGeneration.
+type RemoteKeySet struct {
+	client  *http.Client
+	jwksURI string
+	clock   jwt.Clock
+
+	mu          sync.Mutex
+	set         jwk.Set
+	fetched     bool
+	attemptedAt time.Time
+	fetchErr    error
+	inflight    *keySetFetch
+	generation  uint64
+}
+
+// keySetFetch is fetch of the set shared by concurrent calls, done is closed when err is set.
+type keySetFetch struct {
+	done chan struct{}
+	err  error
+}
+
+type RemoteKeySetOption func(s *RemoteKeySet)
+
+// WithKeySetClock sets clock used to limit refetches.
+func WithKeySetClock(clock jwt.Clock) RemoteKeySetOption {
+	return func(s *RemoteKeySet) {
+		s.clock = clock
+	}
+}
+
+func NewRemoteKeySet(client *http.Client, jwksURI string, opts ...RemoteKeySetOption) *RemoteKeySet {
+	s := &RemoteKeySet{client: client, jwksURI: jwksURI, clock: jwt.SystemClock}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *RemoteKeySet) Keys(ctx context.Context, kid string) ([]jwk.Key, error) {
+	s.mu.Lock()
+	call := s.inflight
+	if call == nil {
+		recent := s.clock.Now().Sub(s.attemptedAt) < minRefreshInterval
+		if s.fetched {
+			keys := matchKeys(s.set, kid)
+			if len(keys) != 0 || recent {
+				s.mu.Unlock()
+				return keys, nil
+			}
+		} else if recent && s.fetchErr != nil {
+			err := s.fetchErr
+			s.mu.Unlock()
+			return nil, err
+		}
+
+		call = &keySetFetch{done: make(chan struct{})}
+		s.inflight = call
+		go s.refresh(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return matchKeys(s.set, kid), nil
+}
+
+// Generation returns number of key set changes.
+func (s *RemoteKeySet) Generation() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.generation
+}
+
+// refresh fetches the set without holding the lock, time of every attempt is recorded to limit refetches.
+func (s *RemoteKeySet) refresh(ctx context.Context, call *keySetFetch) {
+	set, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	s.attemptedAt, s.fetchErr, s.inflight = s.clock.Now(), err, nil
+	if err == nil {
+		if !reflect.DeepEqual(set, s.set) {
+			s.generation++
+		}
+		s.set, s.fetched = set, true
+	}
+	s.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (jwk.Set, error) {
+	var set jwk.Set
+	err := getJSON(ctx, s.client, s.jwksURI, &set)
+	if err != nil {
+		return jwk.Set{}, fmt.Errorf("failed fetch jwks, %w", err)
+	}
+	return set, nil
+}
+
+func matchKeys(set jwk.Set, kid string) []jwk.Key {
+	keys := make([]jwk.Key, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if kid == "" || key.Kid == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,386 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/oidc"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+const clientID = "client"
+
+// provider simulates OpenID Provider with discovery and jwks endpoints.
+type provider struct {
+	*httptest.Server
+	rsaKey     *rsa.PrivateKey
+	es256      *jwttest.Issuer
+	keys       atomic.Pointer[jwk.Set]
+	jwksServed atomic.Int32
+	jwksFailed atomic.Bool
+	// jwksHold delays jwks response until it is closed
+	jwksHold chan struct{}
+}
+
+func newProvider(t *testing.T) *provider {
+	data, err := os.ReadFile("../signingmethods/testdata/sample_key")
+	assert.NilError(t, err)
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(data)
+	assert.NilError(t, err)
+
+	p := &provider{rsaKey: rsaKey}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidc.ProviderMetadata{
+			Issuer:                           p.URL,
+			AuthorizationEndpoint:            p.URL + "/authorize",
+			JWKSURI:                          p.URL + "/jwks",
+			IDTokenSigningAlgValuesSupported: []string{"RS256", "ES256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksServed.Add(1)
+		if p.jwksHold != nil {
+			<-p.jwksHold
+		}
+		if p.jwksFailed.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(p.keys.Load())
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	p.es256 = jwttest.NewIssuer(t, jwttest.WithIssuer(p.URL), jwttest.WithAudience(clientID))
+
+	rsaJWK := jwk.NewRSAPublicKey(&rsaKey.PublicKey)
+	rsaJWK.Kid, rsaJWK.Use = "rsa", "sig"
+	p.keys.Store(&jwk.Set{Keys: append([]jwk.Key{rsaJWK}, p.es256.JWKS().Keys...)})
+
+	return p
+}
+
+// token returns RS256 ID token with kid, claims are added to valid claims of the provider.
+func (p *provider) token(t *testing.T, kid string, claims jwt.Payload) string {
+	now := time.Now()
+	payload := jwt.Payload{
+		"iss": p.URL,
+		"sub": "user",
+		"aud": clientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for claim, value := range claims {
+		if value == nil {
+			delete(payload, claim)
+			continue
+		}
+		payload[claim] = value
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	assert.NilError(t, err)
+	rawPayload, err := json.Marshal(payload)
+	assert.NilError(t, err)
+
+	signingString := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(rawPayload)
+	sign, err := signingmethods.NewRS256(p.rsaKey).Sign(signingString)
+	assert.NilError(t, err)
+
+	return signingString + "." + base64.RawURLEncoding.EncodeToString(sign)
+}
+
+func Test_Verifier(t *testing.T) {
+	ctx := context.Background()
+	p := newProvider(t)
+
+	metadata, err := oidc.Discover(ctx, p.Client(), p.URL)
+	assert.NilError(t, err)
+	assert.Equal(t, metadata.JWKSURI, p.URL+"/jwks")
+
+	now := time.Now()
+	accessToken := "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y"
+	code := "Qcb0Orv1zh30vL1MPRsbm-diHiMwcLyZvn1arpZv-Jxf_11jnpEX3Tgfvk"
+
+	cases := []struct {
+		name        string
+		verifier    *oidc.Verifier
+		token       string
+		opts        []oidc.VerifyOption
+		expectedErr error
+	}{
+		{
+			name:  "valid",
+			token: p.token(t, "rsa", nil),
+		},
+		{
+			name:  "valid without kid",
+			token: p.es256.Token(jwt.Payload{"sub": "user"}),
+		},
+		{
+			name:  "valid hashes and nonce",
+			token: p.token(t, "rsa", jwt.Payload{"nonce": "n-0S6_WzA2Mj", "at_hash": "77QmUPtjPfzWtF2AnpK9RQ", "c_hash": "LDktKdoQak3Pk0cnXxCltA"}),
+			opts:  []oidc.VerifyOption{oidc.WithNonce("n-0S6_WzA2Mj"), oidc.WithAccessToken(accessToken), oidc.WithCode(code)},
+		},
+		{
+			name:  "multiple audiences with azp",
+			token: p.token(t, "rsa", jwt.Payload{"aud": []string{clientID, "api"}, "azp": clientID}),
+		},
+		{
+			name:        "unknown kid",
+			token:       p.token(t, "unknown", nil),
+			expectedErr: oidc.ErrUnknownKey,
+		},
+		{
+			name:        "alg not supported by provider",
+			token:       p.es256.WrongAlgToken(jwt.Payload{"sub": "user"}),
+			expectedErr: jwt.ErrWrongAlgoritm,
+		},
+		{
+			name:        "wrong sign",
+			token:       p.es256.WrongSignatureToken(jwt.Payload{"sub": "user"}),
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+		{
+			name:        "wrong issuer",
+			token:       p.token(t, "rsa", jwt.Payload{"iss": "https://evil.example.com"}),
+			expectedErr: oidc.ErrInvalidIssuer,
+		},
+		{
+			name:        "wrong audience",
+			token:       p.token(t, "rsa", jwt.Payload{"aud": "other"}),
+			expectedErr: oidc.ErrInvalidAudience,
+		},
+		{
+			name:        "multiple audiences without azp",
+			token:       p.token(t, "rsa", jwt.Payload{"aud": []string{clientID, "api"}}),
+			expectedErr: oidc.ErrInvalidAuthorizedParty,
+		},
+		{
+			name:        "wrong azp",
+			token:       p.token(t, "rsa", jwt.Payload{"azp": "other"}),
+			expectedErr: oidc.ErrInvalidAuthorizedParty,
+		},
+		{
+			name:        "expired",
+			token:       p.token(t, "rsa", jwt.Payload{"exp": now.Add(-time.Minute).Unix()}),
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:        "no exp",
+			token:       p.token(t, "rsa", jwt.Payload{"exp": nil}),
+			expectedErr: jwt.ErrNoExpiration,
+		},
+		{
+			name:        "no iat",
+			token:       p.token(t, "rsa", jwt.Payload{"iat": nil}),
+			expectedErr: oidc.ErrNoIssuedAt,
+		},
+		{
+			name:        "wrong nonce",
+			token:       p.token(t, "rsa", jwt.Payload{"nonce": "other"}),
+			opts:        []oidc.VerifyOption{oidc.WithNonce("n-0S6_WzA2Mj")},
+			expectedErr: oidc.ErrInvalidNonce,
+		},
+		{
+			name:        "no nonce",
+			token:       p.token(t, "rsa", nil),
+			opts:        []oidc.VerifyOption{oidc.WithNonce("n-0S6_WzA2Mj")},
+			expectedErr: oidc.ErrInvalidNonce,
+		},
+		{
+			name:        "wrong at_hash",
+			token:       p.token(t, "rsa", jwt.Payload{"at_hash": "LDktKdoQak3Pk0cnXxCltA"}),
+			opts:        []oidc.VerifyOption{oidc.WithAccessToken(accessToken)},
+			expectedErr: oidc.ErrInvalidAccessTokenHash,
+		},
+		{
+			name:        "wrong c_hash",
+			token:       p.token(t, "rsa", jwt.Payload{"c_hash": "77QmUPtjPfzWtF2AnpK9RQ"}),
+			opts:        []oidc.VerifyOption{oidc.WithCode(code)},
+			expectedErr: oidc.ErrInvalidCodeHash,
+		},
+		{
+			name:        "no at_hash",
+			token:       p.token(t, "rsa", nil),
+			opts:        []oidc.VerifyOption{oidc.WithAccessToken(accessToken)},
+			expectedErr: oidc.ErrInvalidAccessTokenHash,
+		},
+		{
+			name:        "no c_hash",
+			token:       p.token(t, "rsa", jwt.Payload{"at_hash": "77QmUPtjPfzWtF2AnpK9RQ"}),
+			opts:        []oidc.VerifyOption{oidc.WithAccessToken(accessToken), oidc.WithCode(code)},
+			expectedErr: oidc.ErrInvalidCodeHash,
+		},
+		{
+			name:     "auth_time within max_age",
+			verifier: oidc.NewProviderVerifier(p.Client(), metadata, clientID, oidc.WithMaxAge(time.Hour)),
+			token:    p.token(t, "rsa", jwt.Payload{"auth_time": now.Add(-time.Minute).Unix()}),
+		},
+		{
+			name:        "no auth_time",
+			verifier:    oidc.NewProviderVerifier(p.Client(), metadata, clientID, oidc.WithMaxAge(time.Hour)),
+			token:       p.token(t, "rsa", nil),
+			expectedErr: oidc.ErrNoAuthTime,
+		},
+		{
+			name:        "auth_time expired",
+			verifier:    oidc.NewProviderVerifier(p.Client(), metadata, clientID, oidc.WithMaxAge(time.Hour)),
+			token:       p.token(t, "rsa", jwt.Payload{"auth_time": now.Add(-2 * time.Hour).Unix()}),
+			expectedErr: oidc.ErrAuthTimeExpired,
+		},
+		{
+			name:     "accepted acr",
+			verifier: oidc.NewProviderVerifier(p.Client(), metadata, clientID, oidc.WithACRValues("urn:mace:incommon:iap:silver", "mfa")),
+			token:    p.token(t, "rsa", jwt.Payload{"acr": "mfa"}),
+		},
+		{
+			name:        "not accepted acr",
+			verifier:    oidc.NewProviderVerifier(p.Client(), metadata, clientID, oidc.WithACRValues("mfa")),
+			token:       p.token(t, "rsa", jwt.Payload{"acr": "pwd"}),
+			expectedErr: oidc.ErrInvalidACR,
+		},
+	}
+
+	verifier := oidc.NewProviderVerifier(p.Client(), metadata, clientID)
+	for _, cs := range cases {
+		if cs.verifier == nil {
+			cs.verifier = verifier
+		}
+		token, err := cs.verifier.Verify(ctx, cs.token, cs.opts...)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr == nil {
+			assert.Equal(t, token.Payload["sub"], "user", cs.name)
+		}
+	}
+}
+
+func Test_Verifier_ErrorCodes(t *testing.T) {
+	codes := map[string]bool{}
+	for _, err := range []jwt.TokenInvalidError{
+		oidc.ErrUnknownKey, oidc.ErrInvalidIssuer, oidc.ErrInvalidAudience, oidc.ErrInvalidAuthorizedParty,
+		oidc.ErrNoIssuedAt, oidc.ErrInvalidNonce, oidc.ErrInvalidAccessTokenHash, oidc.ErrInvalidCodeHash,
+		oidc.ErrNoAuthTime, oidc.ErrAuthTimeExpired, oidc.ErrInvalidACR,
+		jwt.ErrNoExpiration, jwt.ErrTokenExpired, jwt.ErrWrongAlgoritm, jwt.ErrSignNotVerified,
+	} {
+		assert.Assert(t, !codes[err.Code()], err.Code())
+		codes[err.Code()] = true
+	}
+}
+
+func Test_RemoteKeySet_Rotation(t *testing.T) {
+	ctx := context.Background()
+	p := newProvider(t)
+
+	now := time.Now()
+	clock := jwt.ClockFunc(func() time.Time { return now })
+	keySet := oidc.NewRemoteKeySet(p.Client(), p.URL+"/jwks", oidc.WithKeySetClock(clock))
+	verifier := oidc.NewVerifier(p.URL, clientID, keySet)
+
+	_, err := verifier.Verify(ctx, p.token(t, "rsa", nil))
+	assert.NilError(t, err)
+	_, err = verifier.Verify(ctx, p.token(t, "rsa", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, p.jwksServed.Load(), int32(1))
+	assert.Equal(t, keySet.Generation(), uint64(1))
+
+	// provider rotates kid of the key, unknown kid refetches the set
+	rotated := jwk.NewRSAPublicKey(&p.rsaKey.PublicKey)
+	rotated.Kid = "rsa-2"
+	p.keys.Store(&jwk.Set{Keys: []jwk.Key{rotated}})
+	now = now.Add(2 * time.Minute)
+
+	_, err = verifier.Verify(ctx, p.token(t, "rsa-2", nil))
+	assert.NilError(t, err)
+	assert.Equal(t, p.jwksServed.Load(), int32(2))
+	assert.Equal(t, keySet.Generation(), uint64(2))
+
+	// refetch is rate limited
+	_, err = verifier.Verify(ctx, p.token(t, "rsa-3", nil))
+	assert.ErrorIs(t, err, oidc.ErrUnknownKey)
+	assert.Equal(t, p.jwksServed.Load(), int32(2))
+}
+
+func Test_RemoteKeySet_FailedFetch(t *testing.T) {
+	ctx := context.Background()
+	p := newProvider(t)
+	p.jwksFailed.Store(true)
+
+	now := time.Now()
+	keySet := oidc.NewRemoteKeySet(p.Client(), p.URL+"/jwks", oidc.WithKeySetClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	// failed fetch is rate limited as successful one
+	for _, kid := range []string{"rsa", "random-1", "random-2"} {
+		_, err := keySet.Keys(ctx, kid)
+		assert.ErrorContains(t, err, "failed fetch jwks")
+	}
+	assert.Equal(t, p.jwksServed.Load(), int32(1))
+
+	p.jwksFailed.Store(false)
+	now = now.Add(2 * time.Minute)
+
+	keys, err := keySet.Keys(ctx, "rsa")
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 1)
+	assert.Equal(t, p.jwksServed.Load(), int32(2))
+}
+
+func Test_RemoteKeySet_SharedFetch(t *testing.T) {
+	p := newProvider(t)
+	p.jwksHold = make(chan struct{})
+	keySet := oidc.NewRemoteKeySet(p.Client(), p.URL+"/jwks")
+
+	// call is not blocked by slow provider after its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := keySet.Keys(ctx, "rsa")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = keySet.Keys(context.Background(), "unknown")
+		}(i)
+	}
+	close(p.jwksHold)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, p.jwksServed.Load(), int32(1))
+}
+
+func Test_Discover_IssuerMismatch(t *testing.T) {
+	p := newProvider(t)
+
+	_, err := oidc.Discover(context.Background(), p.Client(), p.URL+"/")
+	assert.ErrorIs(t, err, oidc.ErrIssuerMismatch)
+}
+
+func Test_HalfHash(t *testing.T) {
+	atHash, err := oidc.HalfHash("RS256", "jHkWEdUXMU1BwAsC4vtUsZwnNvTIxEl0z9K3vx5KF0Y")
+	assert.NilError(t, err)
+	assert.Equal(t, atHash, "77QmUPtjPfzWtF2AnpK9RQ")
+
+	_, err = oidc.HalfHash("none", "value")
+	assert.ErrorIs(t, err, signingmethods.ErrUnsupportedAlg)
+}
@@ -0,0 +1,297 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var (
+	ErrUnknownKey             jwt.TokenInvalidError = "unknown_key"
+	ErrInvalidIssuer          jwt.TokenInvalidError = "invalid_issuer"
+	ErrInvalidAudience        jwt.TokenInvalidError = "invalid_audience"
+	ErrInvalidAuthorizedParty jwt.TokenInvalidError = "invalid_authorized_party"
+	ErrNoIssuedAt             jwt.TokenInvalidError = "no_issued_at"
+	ErrInvalidNonce           jwt.TokenInvalidError = "invalid_nonce"
+	ErrInvalidAccessTokenHash jwt.TokenInvalidError = "invalid_at_hash"
+	ErrInvalidCodeHash        jwt.TokenInvalidError = "invalid_c_hash"
+	ErrNoAuthTime             jwt.TokenInvalidError = "no_auth_time"
+	ErrAuthTimeExpired        jwt.TokenInvalidError = "auth_time_expired"
+	ErrInvalidACR             jwt.TokenInvalidError = "invalid_acr"
+)
+
+var defaultAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+type VerifierOption func(v *Verifier)
+
+// WithAlgorithms limits algorithms of ID tokens, RS256, RS384, RS512, ES256, ES384 and ES512 are accepted by default.
+func WithAlgorithms(algs ...string) VerifierOption {
+	return func(v *Verifier) {
+		if len(algs) != 0 {
+			v.algs = algs
+		}
+	}
+}
+
+// WithMaxAge requires auth_time claim not older than maxAge, see max_age authentication request parameter.
+func WithMaxAge(maxAge time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// WithACRValues requires acr claim to be one of acrValues.
+func WithACRValues(acrValues ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.acrValues = acrValues
+	}
+}
+
+// WithClock sets clock used to check exp and auth_time claims.
+func WithClock(clock jwt.Clock) VerifierOption {
+	return func(v *Verifier) {
+		v.clock = clock
+	}
+}
+
+// VerifyOption sets values of authentication request and response the ID token is bound to.
+type VerifyOption func(r *verifyRequest)
+
+type verifyRequest struct {
+	nonce       string
+	accessToken string
+	code        string
+}
+
+// WithNonce requires nonce claim equal to nonce sent in authentication request.
+func WithNonce(nonce string) VerifyOption {
+	return func(r *verifyRequest) {
+		r.nonce = nonce
+	}
+}
+
+// WithAccessToken requires at_hash claim of access token issued with the ID token, see OpenID Connect Core 1.0 section 3.2.2.10.
+// at_hash is optional in authorization code flow, so the option is meant for access token returned from authorization endpoint.
+func WithAccessToken(accessToken string) VerifyOption {
+	return func(r *verifyRequest) {
+		r.accessToken = accessToken
+	}
+}
+
+// WithCode requires c_hash claim of authorization code issued with the ID token, see OpenID Connect Core 1.0 section 3.3.2.11.
+func WithCode(code string) VerifyOption {
+	return func(r *verifyRequest) {
+		r.code = code
+	}
+}
+
+// Verifier verifies ID tokens of a single provider for a single client, see OpenID Connect Core 1.0 section 3.1.3.7.
+type Verifier struct {
+	issuer    string
+	clientID  string
+	keySet    KeySet
+	algs      []string
+	maxAge    time.Duration
+	acrValues []string
+	clock     jwt.Clock
+}
+
+func NewVerifier(issuer, clientID string, keySet KeySet, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		issuer:   issuer,
+		clientID: clientID,
+		keySet:   keySet,
+		algs:     defaultAlgorithms,
+		clock:    jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify verifies ID token sign with provider key selected by kid and validates its claims.
+// Token without kid is verified with every provider key.
+func (v *Verifier) Verify(ctx context.Context, idToken string, opts ...VerifyOption) (jwt.Token, error) {
+	var request verifyRequest
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	header, err := decodeHeader(idToken)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+	if !slices.Contains(v.algs, header.Alg) {
+		return jwt.Token{}, &jwt.ParseError{Stage: jwt.StageAlgCheck, Segment: jwt.HeaderSegment, Alg: header.Alg, Err: jwt.ErrWrongAlgoritm}
+	}
+
+	keys, err := v.keySet.Keys(ctx, header.Kid)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+
+	validator := jwt.TokenValidatorFunc(func(token jwt.Token) error {
+		return v.validate(token, request)
+	})
+
+	err = ErrUnknownKey
+	for _, key := range keys {
+		if key.Alg != "" && key.Alg != header.Alg {
+			continue
+		}
+		public, keyErr := key.PublicKey()
+		if keyErr != nil {
+			continue
+		}
+		signingMethod, keyErr := signingmethods.NewVerifier(header.Alg, public)
+		if keyErr != nil {
+			continue
+		}
+
+		var token jwt.Token
+		parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+		token, err = jwt.ParseAndValidateTokenContext(ctx, parser, idToken, validator)
+		if err == nil {
+			return token, nil
+		}
+		if !errors.Is(err, jwt.ErrSignNotVerified) {
+			return jwt.Token{}, err
+		}
+	}
+
+	return jwt.Token{}, err
+}
+
+func (v *Verifier) validate(token jwt.Token, request verifyRequest) error {
+	now := v.clock.Now()
+
+	if iss, _ := token.Payload["iss"].(string); iss != v.issuer {
+		return ErrInvalidIssuer
+	}
+
+	audience, ok := jwt.PayloadAudience(token.Payload)
+	if !ok || !slices.Contains(audience, v.clientID) {
+		return ErrInvalidAudience
+	}
+	azp, hasAZP := token.Payload["azp"].(string)
+	if hasAZP && azp != v.clientID || !hasAZP && len(audience) > 1 {
+		return ErrInvalidAuthorizedParty
+	}
+
+	exp, ok := jwt.PayloadTime(token.Payload, "exp")
+	if !ok {
+		return jwt.ErrNoExpiration
+	}
+	if !now.Before(exp) {
+		return jwt.ErrTokenExpired
+	}
+	if _, ok := jwt.PayloadTime(token.Payload, "iat"); !ok {
+		return ErrNoIssuedAt
+	}
+
+	if request.nonce != "" {
+		if nonce, _ := token.Payload["nonce"].(string); nonce != request.nonce {
+			return ErrInvalidNonce
+		}
+	}
+	if request.accessToken != "" {
+		atHash, ok := token.Payload["at_hash"].(string)
+		if !ok || !verifyHalfHash(token.Header.Alg, request.accessToken, atHash) {
+			return ErrInvalidAccessTokenHash
+		}
+	}
+	if request.code != "" {
+		cHash, ok := token.Payload["c_hash"].(string)
+		if !ok || !verifyHalfHash(token.Header.Alg, request.code, cHash) {
+			return ErrInvalidCodeHash
+		}
+	}
+
+	if v.maxAge > 0 {
+		authTime, ok := jwt.PayloadTime(token.Payload, "auth_time")
+		if !ok {
+			return ErrNoAuthTime
+		}
+		if now.Sub(authTime) > v.maxAge {
+			return ErrAuthTimeExpired
+		}
+	}
+	if len(v.acrValues) != 0 {
+		acr, _ := token.Payload["acr"].(string)
+		if !slices.Contains(v.acrValues, acr) {
+			return ErrInvalidACR
+		}
+	}
+
+	return nil
+}
+
+type idTokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func decodeHeader(idToken string) (idTokenHeader, error) {
+	rawToken, err := jwt.ParseRawToken(idToken)
+	if err != nil {
+		return idTokenHeader{}, err
+	}
+
+	var header idTokenHeader
+	data, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	if err == nil {
+		err = json.Unmarshal(data, &header)
+	}
+	if err != nil {
+		return idTokenHeader{}, &jwt.ParseError{
+			Stage:   jwt.StageDecodeHeader,
+			Segment: jwt.HeaderSegment,
+			Err:     fmt.Errorf("%w: %w", jwt.ErrBadToken, err),
+		}
+	}
+	return header, nil
+}
+
+// HalfHash returns at_hash or c_hash of value, it is base64url left half of value hash by hash function of alg.
+func HalfHash(alg, value string) (string, error) {
+	hash, ok := algHash(alg)
+	if !ok {
+		return "", signingmethods.ErrUnsupportedAlg
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(value))
+	sum := hasher.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
+
+func verifyHalfHash(alg, value, expected string) bool {
+	halfHash, err := HalfHash(alg, value)
+	return err == nil && halfHash == expected
+}
+
+func algHash(alg string) (crypto.Hash, bool) {
+	if len(alg) < 3 {
+		return 0, false
+	}
+	switch alg[len(alg)-3:] {
+	case "256":
+		return crypto.SHA256, true
+	case "384":
+		return crypto.SHA384, true
+	case "512":
+		return crypto.SHA512, true
+	default:
+		return 0, false
+	}
+}
@@ -1,8 +1,8 @@
 package jwt
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"strings"
 )
 
@@ -42,6 +42,11 @@ func NewTokenParser(decoder Decoder, signingMethod SigningMethod) *JwtTokenParse
 }
 
 func (p *JwtTokenParser) ParseToken(accessToken string) (token Token, err error) {
+	return p.ParseTokenContext(context.Background(), accessToken)
+}
+
+// ParseTokenContext parses token, ctx is passed to signing method that implements ContextSigningMethod.
+func (p *JwtTokenParser) ParseTokenContext(ctx context.Context, accessToken string) (token Token, err error) {
 	rawToken, err := ParseRawToken(accessToken)
 	if err != nil {
 		return
@@ -54,7 +59,7 @@ func (p *JwtTokenParser) ParseToken(accessToken string) (token Token, err error)
 	if err != nil {
 		return
 	}
-	err = p.VerifyRawTokenSign(rawToken)
+	err = p.verifyRawTokenSign(ctx, rawToken)
 	if err != nil {
 		return
 	}
@@ -62,49 +67,57 @@ func (p *JwtTokenParser) ParseToken(accessToken string) (token Token, err error)
 }
 
 func ParseRawToken(accessToken string) (RawToken, error) {
-	rawToken := strings.Split(accessToken, ".")
-	if len(rawToken) != 3 {
-		return [3]string{}, ErrBadToken
+	header, rest, ok := strings.Cut(accessToken, ".")
+	if !ok {
+		return RawToken{}, &ParseError{Stage: StageSplit, Segment: NoSegment, Err: ErrBadToken}
+	}
+	payload, sign, ok := strings.Cut(rest, ".")
+	if !ok || strings.IndexByte(sign, '.') != -1 {
+		return RawToken{}, &ParseError{Stage: StageSplit, Segment: NoSegment, Err: ErrBadToken}
 	}
-	return RawToken(rawToken), nil
+	return RawToken{header, payload, sign}, nil
 }
 
 func (p *JwtTokenParser) DecodeToken(rawToken RawToken) (token Token, err error) {
 	header, err := p.decoder.DecodeString(rawToken.Header())
 	if err != nil {
-		return token, fmt.Errorf("failed decode header, %w", err)
+		return token, decodeHeaderError(ErrBadToken, err)
 	}
 	err = json.Unmarshal(header, &token.Header)
 	if err != nil {
-		return token, fmt.Errorf("failed set header, %w", ErrUnmarshalToken)
+		return token, decodeHeaderError(ErrUnmarshalToken, err)
 	}
 	payload, err := p.decoder.DecodeString(rawToken.Payload())
 	if err != nil {
-		return token, fmt.Errorf("failed decode payload, %w", err)
+		return token, decodePayloadError(token.Header, ErrBadToken, err)
 	}
 	err = json.Unmarshal(payload, &token.Payload)
 	if err != nil {
-		return token, fmt.Errorf("failed set payload, %w", ErrUnmarshalToken)
+		return token, decodePayloadError(token.Header, ErrUnmarshalToken, err)
 	}
 	return
 }
 
 func (p *JwtTokenParser) VerifyHeaderAlg(header Header) error {
 	if p.signingMethod.Alg() != header.Alg {
-		return ErrWrongAlgoritm
+		return &ParseError{Stage: StageAlgCheck, Segment: HeaderSegment, Alg: header.Alg, Err: ErrWrongAlgoritm}
 	}
 	return nil
 }
 
 func (p *JwtTokenParser) VerifyRawTokenSign(rawToken RawToken) error {
+	return p.verifyRawTokenSign(context.Background(), rawToken)
+}
+
+func (p *JwtTokenParser) verifyRawTokenSign(ctx context.Context, rawToken RawToken) error {
 	sign, err := p.decoder.DecodeString(rawToken.Sign())
 	if err != nil {
-		return fmt.Errorf("failed decode sign segment, %w", err)
+		return signatureError(p.signingMethod.Alg(), ErrBadToken, err)
 	}
 	signed := rawToken.Header() + "." + rawToken.Payload()
-	err = p.signingMethod.Verify(signed, sign)
+	err = verifyContext(ctx, p.signingMethod, signed, sign)
 	if err != nil {
-		return fmt.Errorf("failed verify token sign, %w", err)
+		return verifyError(ctx, p.signingMethod.Alg(), err)
 	}
 	return nil
 }
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"unsafe"
+)
+
+// bufferDecoder is implemented by *base64.Encoding, it lets parser decode segments into reused buffers.
+type bufferDecoder interface {
+	DecodedLen(n int) int
+	Decode(dst, src []byte) (int, error)
+}
+
+// maxPooledSegmentSize keeps buffers of unusually large tokens out of the pool.
+const maxPooledSegmentSize = 64 << 10
+
+var segmentBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
+// ParseClaims verifies token and decodes its payload into claims, e.g. pointer to struct or to Payload reused between calls.
+// Unlike ParseToken it verifies sign before the payload is decoded, signing input is sliced from the token instead of concatenated,
+// segments are decoded into pooled buffers when decoder is *base64.Encoding.
+func (p *JwtTokenParser) ParseClaims(accessToken string, claims any) (header Header, err error) {
+	return p.ParseClaimsContext(context.Background(), accessToken, claims)
+}
+
+// ParseClaimsContext is ParseClaims that passes ctx to signing method that implements ContextSigningMethod.
+func (p *JwtTokenParser) ParseClaimsContext(ctx context.Context, accessToken string, claims any) (header Header, err error) {
+	rawToken, err := ParseRawToken(accessToken)
+	if err != nil {
+		return header, err
+	}
+
+	bufPtr := segmentBufferPool.Get().(*[]byte)
+	buf := *bufPtr
+	defer func() {
+		if cap(buf) <= maxPooledSegmentSize {
+			*bufPtr = buf[:0]
+			segmentBufferPool.Put(bufPtr)
+		}
+	}()
+
+	buf, err = p.decodeSegment(buf, rawToken.Header())
+	if err != nil {
+		return header, decodeHeaderError(ErrBadToken, err)
+	}
+	err = json.Unmarshal(buf, &header)
+	if err != nil {
+		return header, decodeHeaderError(ErrUnmarshalToken, err)
+	}
+	err = p.VerifyHeaderAlg(header)
+	if err != nil {
+		return header, err
+	}
+
+	buf, err = p.decodeSegment(buf, rawToken.Sign())
+	if err != nil {
+		return header, signatureError(header.Alg, ErrBadToken, err)
+	}
+	signingInput := accessToken[:len(rawToken.Header())+1+len(rawToken.Payload())]
+	err = verifyContext(ctx, p.signingMethod, signingInput, buf)
+	if err != nil {
+		return header, verifyError(ctx, header.Alg, err)
+	}
+
+	buf, err = p.decodeSegment(buf, rawToken.Payload())
+	if err != nil {
+		return header, decodePayloadError(header, ErrBadToken, err)
+	}
+	err = json.Unmarshal(buf, claims)
+	if err != nil {
+		return header, decodePayloadError(header, ErrUnmarshalToken, err)
+	}
+
+	return header, nil
+}
+
+// decodeSegment decodes segment over buf, buf is grown when it is too small.
+// Segment bytes are passed to decoder without copy, Decode does not modify its src.
+func (p *JwtTokenParser) decodeSegment(buf []byte, segment string) ([]byte, error) {
+	decoder, ok := p.decoder.(bufferDecoder)
+	if !ok {
+		return p.decoder.DecodeString(segment)
+	}
+
+	size := decoder.DecodedLen(len(segment))
+	if cap(buf) < size {
+		buf = make([]byte, size)
+	}
+	n, err := decoder.Decode(buf[:size], unsafe.Slice(unsafe.StringData(segment), len(segment)))
+	if err != nil {
+		return buf[:0], err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type ParseStage string
+
+const (
+	StageSplit         ParseStage = "split"
+	StageDecodeHeader  ParseStage = "decode_header"
+	StageDecodePayload ParseStage = "decode_payload"
+	StageAlgCheck      ParseStage = "alg_check"
+	StageSignature     ParseStage = "signature"
+	StageValidation    ParseStage = "validation"
+)
+
+// Indexes of compact token segments, NoSegment is used when failed stage is not bound to single segment.
+const (
+	NoSegment      = -1
+	HeaderSegment  = 0
+	PayloadSegment = 1
+	SignSegment    = 2
+)
+
+// ParseError reports stage and segment of token parse that failed.
+// Err is TokenInvalidError or wraps it, so errors.Is(err, ErrBadToken) and similar checks hold for ParseError.
+// Signature stage error is not TokenInvalidError when verification was not done, e.g. context was cancelled or ErrSigningMethodUnavailable.
+type ParseError struct {
+	Stage   ParseStage
+	Segment int
+	// Alg is alg from token header, it is empty when header was not decoded
+	Alg string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed %s stage", e.Stage)
+	if e.Segment != NoSegment {
+		fmt.Fprintf(&b, ", segment %d", e.Segment)
+	}
+	if e.Alg != "" {
+		fmt.Fprintf(&b, ", alg %s", e.Alg)
+	}
+	fmt.Fprintf(&b, ", %s", e.Err)
+	return b.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseAndValidateToken parses token and validates it, validation errors are returned as ParseError with StageValidation.
+func ParseAndValidateToken(parser TokenParser, accessToken string, tokenValidators ...TokenValidator) (Token, error) {
+	token, err := parser.ParseToken(accessToken)
+	if err != nil {
+		return token, err
+	}
+	err = ValidateToken(token, tokenValidators...)
+	if err != nil {
+		return token, &ParseError{Stage: StageValidation, Segment: NoSegment, Alg: token.Header.Alg, Err: err}
+	}
+	return token, nil
+}
+
+func decodeHeaderError(code TokenInvalidError, err error) error {
+	return &ParseError{Stage: StageDecodeHeader, Segment: HeaderSegment, Err: wrapTokenInvalid(code, err)}
+}
+
+func decodePayloadError(header Header, code TokenInvalidError, err error) error {
+	return &ParseError{Stage: StageDecodePayload, Segment: PayloadSegment, Alg: header.Alg, Err: wrapTokenInvalid(code, err)}
+}
+
+func signatureError(alg string, code TokenInvalidError, err error) error {
+	return &ParseError{Stage: StageSignature, Segment: SignSegment, Alg: alg, Err: wrapTokenInvalid(code, err)}
+}
+
+// verifyError reports failed signature verification, errors of cancelled ctx and unavailable signing method are not ErrSignNotVerified.
+func verifyError(ctx context.Context, alg string, err error) error {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrSigningMethodUnavailable) {
+		return &ParseError{Stage: StageSignature, Segment: SignSegment, Alg: alg, Err: err}
+	}
+	return signatureError(alg, ErrSignNotVerified, err)
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source replay.go -destination mocks/replay_mocks.go -package jwtmocks
+
+var ErrTokenReplayed TokenInvalidError = "replayed"
+
+// ReplayStore records token ids until expiresAt.
+// Record must be atomic: for concurrent calls with the same jti only one reports first use.
+// Record must not report first use of jti with expiresAt in the past, such jti could not be remembered.
+type ReplayStore interface {
+	Record(jti string, expiresAt time.Time) (firstUse bool, err error)
+}
+
+// ContextReplayStore is implemented by stores that accept context, ReplayValidator passes context to it in ValidateTokenContext.
+type ContextReplayStore interface {
+	RecordContext(ctx context.Context, jti string, expiresAt time.Time) (firstUse bool, err error)
+}
+
+// ReplayValidator accepts every token only once, it is intended for single-use tokens.
+// Token must have jti and exp claims, the jti is remembered until the token expires, so expired token is rejected.
+type ReplayValidator struct {
+	store ReplayStore
+}
+
+func NewReplayValidator(store ReplayStore) *ReplayValidator {
+	return &ReplayValidator{store: store}
+}
+
+func (v *ReplayValidator) ValidateToken(token Token) error {
+	return v.ValidateTokenContext(context.Background(), token)
+}
+
+// ValidateTokenContext is ValidateToken that passes ctx to store that implements ContextReplayStore.
+func (v *ReplayValidator) ValidateTokenContext(ctx context.Context, token Token) error {
+	jti, ok := payloadString(token.Payload, "jti")
+	if !ok {
+		return ErrNoTokenID
+	}
+	exp, ok := PayloadTime(token.Payload, "exp")
+	if !ok {
+		return ErrNoExpiration
+	}
+	if !exp.After(time.Now()) {
+		return ErrTokenExpired
+	}
+	firstUse, err := RecordTokenID(ctx, v.store, jti, exp)
+	if err != nil {
+		return fmt.Errorf("failed record token id, %w", err)
+	}
+	if !firstUse {
+		return ErrTokenReplayed
+	}
+	return nil
+}
+
+// RecordTokenID records jti in store, ctx is passed to store that implements ContextReplayStore.
+// Other stores are not called when ctx is done.
+func RecordTokenID(ctx context.Context, store ReplayStore, jti string, expiresAt time.Time) (firstUse bool, err error) {
+	if store, ok := store.(ContextReplayStore); ok {
+		return store.RecordContext(ctx, jti, expiresAt)
+	}
+	err = ctx.Err()
+	if err != nil {
+		return false, err
+	}
+	return store.Record(jti, expiresAt)
+}
+
+// MemoryReplayStore is ReplayStore that keeps token ids in memory, safe for concurrent use.
+type MemoryReplayStore struct {
+	mu        sync.Mutex
+	ids       map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{
+		ids:       make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryReplayStore) Record(jti string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if current, ok := s.ids[jti]; ok && !expired(current, now) {
+		return false, nil
+	}
+	if expired(expiresAt, now) {
+		return false, nil
+	}
+	s.ids[jti] = expiresAt
+	return true, nil
+}
+
+func (s *MemoryReplayStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for jti, expiresAt := range s.ids {
+		if expired(expiresAt, now) {
+			delete(s.ids, jti)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source revocation.go -destination mocks/revocation_mocks.go -package jwtmocks
+
+var (
+	ErrTokenRevoked TokenInvalidError = "revoked"
+	ErrNoTokenID    TokenInvalidError = "no_token_id"
+)
+
+// RevocationStore keeps revoked token ids and subjects until the tokens they cover expire.
+// Zero expiresAt means the entry never expires.
+type RevocationStore interface {
+	RevokeID(jti string, expiresAt time.Time) error
+	RevokeSubject(sub string, issuedBefore, expiresAt time.Time) error
+	IsIDRevoked(jti string) (bool, error)
+	SubjectRevokedBefore(sub string) (issuedBefore time.Time, revoked bool, err error)
+}
+
+// ContextRevocationStore is implemented by stores that accept context on checks, e.g. remote stores.
+// RevocationValidator passes context to it in ValidateTokenContext.
+type ContextRevocationStore interface {
+	IsIDRevokedContext(ctx context.Context, jti string) (bool, error)
+	SubjectRevokedBeforeContext(ctx context.Context, sub string) (issuedBefore time.Time, revoked bool, err error)
+}
+
+// RevokeToken revokes token by its jti claim until the token exp.
+func RevokeToken(store RevocationStore, token Token) error {
+	jti, ok := payloadString(token.Payload, "jti")
+	if !ok {
+		return ErrNoTokenID
+	}
+	exp, _ := PayloadTime(token.Payload, "exp")
+	return store.RevokeID(jti, exp)
+}
+
+type RevocationValidator struct {
+	store RevocationStore
+}
+
+func NewRevocationValidator(store RevocationStore) *RevocationValidator {
+	return &RevocationValidator{store: store}
+}
+
+// ValidateToken rejects tokens revoked by jti and tokens of revoked subjects issued before the revocation.
+// Token without iat is treated as issued before any subject revocation.
+func (v *RevocationValidator) ValidateToken(token Token) error {
+	return v.ValidateTokenContext(context.Background(), token)
+}
+
+// ValidateTokenContext is ValidateToken that passes ctx to store that implements ContextRevocationStore.
+func (v *RevocationValidator) ValidateTokenContext(ctx context.Context, token Token) error {
+	if jti, ok := payloadString(token.Payload, "jti"); ok {
+		revoked, err := v.isIDRevoked(ctx, jti)
+		if err != nil {
+			return fmt.Errorf("failed check token id revocation, %w", err)
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	sub, ok := payloadString(token.Payload, "sub")
+	if !ok {
+		return nil
+	}
+	issuedBefore, revoked, err := v.subjectRevokedBefore(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("failed check subject revocation, %w", err)
+	}
+	if !revoked {
+		return nil
+	}
+	iat, ok := PayloadTime(token.Payload, "iat")
+	if !ok || iat.Before(issuedBefore) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+func (v *RevocationValidator) isIDRevoked(ctx context.Context, jti string) (bool, error) {
+	if store, ok := v.store.(ContextRevocationStore); ok {
+		return store.IsIDRevokedContext(ctx, jti)
+	}
+	err := ctx.Err()
+	if err != nil {
+		return false, err
+	}
+	return v.store.IsIDRevoked(jti)
+}
+
+func (v *RevocationValidator) subjectRevokedBefore(ctx context.Context, sub string) (time.Time, bool, error) {
+	if store, ok := v.store.(ContextRevocationStore); ok {
+		return store.SubjectRevokedBeforeContext(ctx, sub)
+	}
+	err := ctx.Err()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return v.store.SubjectRevokedBefore(sub)
+}
+
+const memoryStoreSweepInterval = time.Minute
+
+type subjectRevocation struct {
+	issuedBefore time.Time
+	expiresAt    time.Time
+}
+
+// MemoryRevocationStore is RevocationStore that keeps entries in memory and drops them after expiration.
+type MemoryRevocationStore struct {
+	mu        sync.Mutex
+	ids       map[string]time.Time
+	subjects  map[string]subjectRevocation
+	lastSweep time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		ids:       make(map[string]time.Time),
+		subjects:  make(map[string]subjectRevocation),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryRevocationStore) RevokeID(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if expired(expiresAt, now) {
+		return nil
+	}
+	if current, ok := s.ids[jti]; ok && outlives(current, expiresAt) {
+		return nil
+	}
+	s.ids[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeSubject(sub string, issuedBefore, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	if expired(expiresAt, now) {
+		return nil
+	}
+	revocation := subjectRevocation{issuedBefore: issuedBefore, expiresAt: expiresAt}
+	if current, ok := s.subjects[sub]; ok {
+		if current.issuedBefore.After(revocation.issuedBefore) {
+			revocation.issuedBefore = current.issuedBefore
+		}
+		if outlives(current.expiresAt, revocation.expiresAt) {
+			revocation.expiresAt = current.expiresAt
+		}
+	}
+	s.subjects[sub] = revocation
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsIDRevoked(jti string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.ids[jti]
+	if !ok {
+		return false, nil
+	}
+	if expired(expiresAt, time.Now()) {
+		delete(s.ids, jti)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *MemoryRevocationStore) SubjectRevokedBefore(sub string) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revocation, ok := s.subjects[sub]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if expired(revocation.expiresAt, time.Now()) {
+		delete(s.subjects, sub)
+		return time.Time{}, false, nil
+	}
+	return revocation.issuedBefore, true, nil
+}
+
+func (s *MemoryRevocationStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for jti, expiresAt := range s.ids {
+		if expired(expiresAt, now) {
+			delete(s.ids, jti)
+		}
+	}
+	for sub, revocation := range s.subjects {
+		if expired(revocation.expiresAt, now) {
+			delete(s.subjects, sub)
+		}
+	}
+}
+
+func expired(expiresAt, now time.Time) bool {
+	return !expiresAt.IsZero() && !expiresAt.After(now)
+}
+
+func outlives(current, expiresAt time.Time) bool {
+	return current.IsZero() || (!expiresAt.IsZero() && current.After(expiresAt))
+}
@@ -0,0 +1,132 @@
+package sdjwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// saltSize is 128 bits, see draft-ietf-oauth-selective-disclosure-jwt section 9.3.
+const saltSize = 16
+
+var ErrReservedClaimName = errors.New("_sd and ... claim names are reserved")
+
+// Disclosure is salted claim or array element, see draft-ietf-oauth-selective-disclosure-jwt section 4.2.
+type Disclosure struct {
+	Salt string
+	// Name is claim name, it is empty for array element disclosure
+	Name  string
+	Value any
+
+	arrayElement bool
+	encoded      string
+}
+
+// NewDisclosure returns disclosure of object property with random salt.
+func NewDisclosure(name string, value any) (Disclosure, error) {
+	if reservedClaimName(name) {
+		return Disclosure{}, ErrReservedClaimName
+	}
+	salt, err := newSalt()
+	if err != nil {
+		return Disclosure{}, err
+	}
+	return newDisclosure(Disclosure{Salt: salt, Name: name, Value: value}, []any{salt, name, value})
+}
+
+// NewArrayElementDisclosure returns disclosure of array element with random salt.
+func NewArrayElementDisclosure(value any) (Disclosure, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return Disclosure{}, err
+	}
+	return newDisclosure(Disclosure{Salt: salt, Value: value, arrayElement: true}, []any{salt, value})
+}
+
+func newDisclosure(disclosure Disclosure, array []any) (Disclosure, error) {
+	data, err := json.Marshal(array)
+	if err != nil {
+		return Disclosure{}, fmt.Errorf("failed marshal disclosure, %w", err)
+	}
+	// value is decoded back, so it has the same types as value of parsed disclosure
+	err = json.Unmarshal(data, &array)
+	if err != nil {
+		return Disclosure{}, fmt.Errorf("failed unmarshal disclosure, %w", err)
+	}
+	disclosure.Value = array[len(array)-1]
+	disclosure.encoded = base64.RawURLEncoding.EncodeToString(data)
+	return disclosure, nil
+}
+
+// ParseDisclosure decodes base64url disclosure, the encoded form is kept as is because digest is calculated over it.
+func ParseDisclosure(encoded string) (Disclosure, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Disclosure{}, fmt.Errorf("%w: %w", ErrInvalidDisclosure, err)
+	}
+
+	var array []any
+	err = json.Unmarshal(data, &array)
+	if err != nil {
+		return Disclosure{}, fmt.Errorf("%w: %w", ErrInvalidDisclosure, err)
+	}
+
+	disclosure := Disclosure{encoded: encoded}
+	var ok bool
+	switch len(array) {
+	case 2:
+		disclosure.arrayElement = true
+		disclosure.Salt, ok = array[0].(string)
+		disclosure.Value = array[1]
+	case 3:
+		disclosure.Salt, ok = array[0].(string)
+		if !ok {
+			break
+		}
+		disclosure.Name, ok = array[1].(string)
+		ok = ok && !reservedClaimName(disclosure.Name)
+		disclosure.Value = array[2]
+	}
+	if !ok {
+		return Disclosure{}, ErrInvalidDisclosure
+	}
+
+	return disclosure, nil
+}
+
+// ArrayElement reports whether disclosure is array element, array element disclosure has no name.
+func (d Disclosure) ArrayElement() bool {
+	return d.arrayElement
+}
+
+// String returns base64url encoded disclosure.
+func (d Disclosure) String() string {
+	return d.encoded
+}
+
+// Digest returns base64url hash of encoded disclosure that is embedded into issuer-signed JWT.
+func (d Disclosure) Digest(hash crypto.Hash) string {
+	return digest(hash, d.encoded)
+}
+
+func digest(hash crypto.Hash, value string) string {
+	h := hash.New()
+	h.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func newSalt() (string, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("failed generate salt, %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(salt), nil
+}
+
+func reservedClaimName(name string) bool {
+	return name == sdClaim || name == arrayElementKey
+}
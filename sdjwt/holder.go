@@ -0,0 +1,106 @@
+package sdjwt
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+// KeyBindingType is typ header of key binding JWT, see draft-ietf-oauth-selective-disclosure-jwt section 4.3.
+const KeyBindingType = "kb+jwt"
+
+// Select returns SD-JWT without key binding that keeps only disclosures needed to reveal values at JSON pointers, see RFC 6901.
+// Pointers address values of issuer payload, e.g. /address/street_address or /nationalities/1 where index counts concealed elements,
+// disclosures of parent values are kept too.
+func (s SDJWT) Select(pointers ...string) (SDJWT, error) {
+	payload, err := unverifiedPayload(s.Token)
+	if err != nil {
+		return SDJWT{}, err
+	}
+	hash, err := payloadHash(payload)
+	if err != nil {
+		return SDJWT{}, err
+	}
+	_, refs, err := disclose(payload, hash, s.Disclosures)
+	if err != nil {
+		return SDJWT{}, err
+	}
+
+	selected := SDJWT{Token: s.Token, Disclosures: make([]Disclosure, 0, len(pointers))}
+	for _, disclosure := range s.Disclosures {
+		ref := refs[disclosure.Digest(hash)]
+		if selectedPointer(ref.pointer, pointers) {
+			selected.Disclosures = append(selected.Disclosures, disclosure)
+		}
+	}
+	return selected, nil
+}
+
+// selectedPointer reports whether disclosed value at pointer is one of pointers or their parent.
+func selectedPointer(pointer string, pointers []string) bool {
+	for _, selected := range pointers {
+		if selected == pointer || strings.HasPrefix(selected, pointer+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type HolderOption func(h *holderConfig)
+
+type holderConfig struct {
+	clock jwt.Clock
+}
+
+// WithClock sets clock used for iat claim of key binding JWT.
+func WithClock(clock jwt.Clock) HolderOption {
+	return func(h *holderConfig) {
+		h.clock = clock
+	}
+}
+
+// Holder presents SD-JWTs with key binding JWT signed by key from cnf claim of issuer-signed JWT.
+type Holder struct {
+	creator *jwt.JwtTokenCreator
+	clock   jwt.Clock
+}
+
+func NewHolder(signingMethod jwt.SigningMethod, opts ...HolderOption) *Holder {
+	config := holderConfig{clock: jwt.SystemClock}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Holder{
+		creator: jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, jwt.WithHeaderType(KeyBindingType)),
+		clock:   config.clock,
+	}
+}
+
+// Present returns serialized SD-JWT with key binding JWT for verifier audience and its nonce, see draft-ietf-oauth-selective-disclosure-jwt section 4.3.
+// Disclosures should be chosen before by Select.
+func (h *Holder) Present(ctx context.Context, sdJWT SDJWT, audience, nonce string) (string, error) {
+	payload, err := unverifiedPayload(sdJWT.Token)
+	if err != nil {
+		return "", err
+	}
+	hash, err := payloadHash(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sdJWT.KeyBinding = ""
+	presentation := sdJWT.withoutKeyBinding()
+	keyBinding, err := h.creator.CreateTokenContext(ctx, jwt.Payload{
+		"iat":     h.clock.Now().Unix(),
+		"aud":     audience,
+		"nonce":   nonce,
+		"sd_hash": digest(hash, presentation),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return presentation + keyBinding, nil
+}
@@ -0,0 +1,191 @@
+package sdjwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"fmt"
+	"slices"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+)
+
+// Disclosable marks payload value as selectively disclosable, Value could contain nested Disclosable values.
+// Only jwt.Payload, map[string]any and []any values are walked by Issuer.
+type Disclosable struct {
+	Value any
+}
+
+type IssuerOption func(i *Issuer)
+
+// WithHashAlg sets hash of disclosure digests, SHA-256 is used by default.
+func WithHashAlg(hash crypto.Hash) IssuerOption {
+	return func(i *Issuer) {
+		i.hash = hash
+	}
+}
+
+// WithDecoys adds count decoy digests to every _sd claim, so holder can't learn the number of concealed claims.
+func WithDecoys(count int) IssuerOption {
+	return func(i *Issuer) {
+		i.decoys = count
+	}
+}
+
+// Issuer issues SD-JWTs, issuer-signed JWT is created by JwtTokenCreator or other creator,
+// e.g. with WithHeaderType("dc+sd-jwt") for SD-JWT VC.
+type Issuer struct {
+	creator jwt.ContextTokenCreator
+	hash    crypto.Hash
+	decoys  int
+}
+
+func NewIssuer(creator jwt.ContextTokenCreator, opts ...IssuerOption) *Issuer {
+	i := &Issuer{
+		creator: creator,
+		hash:    crypto.SHA256,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+type IssueOption func(payload jwt.Payload)
+
+// WithHolderKey sets cnf claim to public key of holder that signs key binding JWTs.
+func WithHolderKey(key jwk.Key) IssueOption {
+	return func(payload jwt.Payload) {
+		payload["cnf"] = map[string]any{"jwk": key.Public()}
+	}
+}
+
+// Issue conceals Disclosable values of payload and returns SD-JWT with all disclosures.
+// Registered claims set by creator, e.g. iss and exp, must not be Disclosable.
+func (i *Issuer) Issue(ctx context.Context, payload jwt.Payload, opts ...IssueOption) (SDJWT, error) {
+	algName, err := HashAlgName(i.hash)
+	if err != nil {
+		return SDJWT{}, err
+	}
+	if _, ok := payload[sdAlgClaim]; ok {
+		return SDJWT{}, ErrReservedClaimName
+	}
+
+	var disclosures []Disclosure
+	concealed, err := i.concealObject(payload, &disclosures)
+	if err != nil {
+		return SDJWT{}, err
+	}
+
+	concealedPayload := jwt.Payload(concealed)
+	concealedPayload[sdAlgClaim] = algName
+	for _, opt := range opts {
+		opt(concealedPayload)
+	}
+
+	token, err := i.creator.CreateTokenContext(ctx, concealedPayload)
+	if err != nil {
+		return SDJWT{}, err
+	}
+
+	return SDJWT{Token: token, Disclosures: disclosures}, nil
+}
+
+func (i *Issuer) concealObject(object map[string]any, disclosures *[]Disclosure) (map[string]any, error) {
+	result := make(map[string]any, len(object)+1)
+	digests := make([]string, 0, i.decoys)
+	for name, value := range object {
+		if reservedClaimName(name) {
+			return nil, ErrReservedClaimName
+		}
+
+		disclosable, ok := value.(Disclosable)
+		if !ok {
+			value, err := i.conceal(value, disclosures)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = value
+			continue
+		}
+
+		value, err := i.conceal(disclosable.Value, disclosures)
+		if err != nil {
+			return nil, err
+		}
+		disclosure, err := NewDisclosure(name, value)
+		if err != nil {
+			return nil, err
+		}
+		*disclosures = append(*disclosures, disclosure)
+		digests = append(digests, disclosure.Digest(i.hash))
+	}
+
+	if len(digests) == 0 && i.decoys == 0 {
+		return result, nil
+	}
+
+	for j := 0; j < i.decoys; j++ {
+		decoy, err := i.decoyDigest()
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, decoy)
+	}
+	// sorting hides original order of claims, see draft-ietf-oauth-selective-disclosure-jwt section 4.2.4.1
+	slices.Sort(digests)
+	result[sdClaim] = digests
+
+	return result, nil
+}
+
+func (i *Issuer) concealArray(array []any, disclosures *[]Disclosure) ([]any, error) {
+	result := make([]any, 0, len(array))
+	for _, element := range array {
+		disclosable, ok := element.(Disclosable)
+		if !ok {
+			value, err := i.conceal(element, disclosures)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		value, err := i.conceal(disclosable.Value, disclosures)
+		if err != nil {
+			return nil, err
+		}
+		disclosure, err := NewArrayElementDisclosure(value)
+		if err != nil {
+			return nil, err
+		}
+		*disclosures = append(*disclosures, disclosure)
+		result = append(result, map[string]any{arrayElementKey: disclosure.Digest(i.hash)})
+	}
+	return result, nil
+}
+
+func (i *Issuer) conceal(value any, disclosures *[]Disclosure) (any, error) {
+	switch value := value.(type) {
+	case jwt.Payload:
+		return i.concealObject(value, disclosures)
+	case map[string]any:
+		return i.concealObject(value, disclosures)
+	case []any:
+		return i.concealArray(value, disclosures)
+	default:
+		return value, nil
+	}
+}
+
+// decoyDigest returns digest of random value, see draft-ietf-oauth-selective-disclosure-jwt section 4.2.5.
+func (i *Issuer) decoyDigest() (string, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", fmt.Errorf("failed generate decoy digest, %w", err)
+	}
+	return digest(i.hash, string(salt)), nil
+}
@@ -0,0 +1,182 @@
+package sdjwt
+
+import (
+	"crypto"
+	"strconv"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+type disclosureRef struct {
+	disclosure Disclosure
+	used       bool
+	// pointer is JSON pointer of disclosed value in payload, see RFC 6901
+	pointer string
+}
+
+// processor rebuilds payload from disclosures, see draft-ietf-oauth-selective-disclosure-jwt section 7.1.
+type processor struct {
+	refs        map[string]*disclosureRef
+	seenDigests map[string]struct{}
+}
+
+// disclose returns payload with disclosed claims, _sd and _sd_alg claims and undisclosed digests are removed.
+// Every disclosure must be referenced exactly once and every digest must be unique.
+func disclose(payload jwt.Payload, hash crypto.Hash, disclosures []Disclosure) (jwt.Payload, map[string]*disclosureRef, error) {
+	p := processor{
+		refs:        make(map[string]*disclosureRef, len(disclosures)),
+		seenDigests: make(map[string]struct{}),
+	}
+	for _, disclosure := range disclosures {
+		digest := disclosure.Digest(hash)
+		if _, ok := p.refs[digest]; ok {
+			return nil, nil, ErrDuplicateDigest
+		}
+		p.refs[digest] = &disclosureRef{disclosure: disclosure}
+	}
+
+	object, err := p.processObject(payload, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(object, sdAlgClaim)
+
+	for _, ref := range p.refs {
+		if !ref.used {
+			return nil, nil, ErrUnreferencedDisclosure
+		}
+	}
+
+	return object, p.refs, nil
+}
+
+func (p *processor) processObject(object map[string]any, pointer string) (map[string]any, error) {
+	result := make(map[string]any, len(object))
+	for name, value := range object {
+		if name == sdClaim {
+			continue
+		}
+		value, err := p.process(value, pointer+"/"+escapePointer(name))
+		if err != nil {
+			return nil, err
+		}
+		result[name] = value
+	}
+
+	sd, ok := object[sdClaim]
+	if !ok {
+		return result, nil
+	}
+	digests, ok := sd.([]any)
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+	for _, value := range digests {
+		digest, ok := value.(string)
+		if !ok {
+			return nil, ErrInvalidFormat
+		}
+		ref, err := p.reference(digest)
+		if err != nil {
+			return nil, err
+		}
+		// digest without disclosure is undisclosed claim or decoy
+		if ref == nil {
+			continue
+		}
+		if ref.disclosure.ArrayElement() {
+			return nil, ErrInvalidDisclosure
+		}
+		if _, ok := result[ref.disclosure.Name]; ok {
+			return nil, ErrInvalidDisclosure
+		}
+
+		ref.pointer = pointer + "/" + escapePointer(ref.disclosure.Name)
+		value, err := p.process(ref.disclosure.Value, ref.pointer)
+		if err != nil {
+			return nil, err
+		}
+		result[ref.disclosure.Name] = value
+	}
+
+	return result, nil
+}
+
+func (p *processor) processArray(array []any, pointer string) ([]any, error) {
+	result := make([]any, 0, len(array))
+	for index, element := range array {
+		elementPointer := pointer + "/" + strconv.Itoa(index)
+
+		digest, ok := arrayElementDigest(element)
+		if !ok {
+			value, err := p.process(element, elementPointer)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		ref, err := p.reference(digest)
+		if err != nil {
+			return nil, err
+		}
+		if ref == nil {
+			continue
+		}
+		if !ref.disclosure.ArrayElement() {
+			return nil, ErrInvalidDisclosure
+		}
+
+		ref.pointer = elementPointer
+		value, err := p.process(ref.disclosure.Value, elementPointer)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func (p *processor) process(value any, pointer string) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		return p.processObject(value, pointer)
+	case []any:
+		return p.processArray(value, pointer)
+	default:
+		return value, nil
+	}
+}
+
+// reference marks disclosure of digest as used, nil is returned when there is no such disclosure.
+func (p *processor) reference(digest string) (*disclosureRef, error) {
+	if _, ok := p.seenDigests[digest]; ok {
+		return nil, ErrDuplicateDigest
+	}
+	p.seenDigests[digest] = struct{}{}
+
+	ref, ok := p.refs[digest]
+	if !ok {
+		return nil, nil
+	}
+	ref.used = true
+	return ref, nil
+}
+
+// arrayElementDigest returns digest of {"...": "<digest>"} array element.
+func arrayElementDigest(element any) (string, bool) {
+	object, ok := element.(map[string]any)
+	if !ok || len(object) != 1 {
+		return "", false
+	}
+	digest, ok := object[arrayElementKey].(string)
+	return digest, ok
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointer(name string) string {
+	return pointerEscaper.Replace(name)
+}
@@ -0,0 +1,137 @@
+// Package sdjwt implements Selective Disclosure for JWTs, see draft-ietf-oauth-selective-disclosure-jwt.
+//
+// Issuer conceals Disclosable values of payload behind digests, holder presents subset of disclosures
+// optionally with key binding JWT, verifier checks the issuer-signed JWT and rebuilds payload from the disclosures.
+package sdjwt
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/amidgo/jwt"
+)
+
+const (
+	sdClaim         = "_sd"
+	sdAlgClaim      = "_sd_alg"
+	arrayElementKey = "..."
+	separator       = "~"
+)
+
+var (
+	ErrInvalidFormat          jwt.TokenInvalidError = "invalid_sd_jwt"
+	ErrInvalidDisclosure      jwt.TokenInvalidError = "invalid_disclosure"
+	ErrUnreferencedDisclosure jwt.TokenInvalidError = "unreferenced_disclosure"
+	ErrDuplicateDigest        jwt.TokenInvalidError = "duplicate_digest"
+	ErrUnsupportedHashAlg     jwt.TokenInvalidError = "unsupported_sd_alg"
+)
+
+// SDJWT is issued SD-JWT or presentation, see draft-ietf-oauth-selective-disclosure-jwt section 4.
+type SDJWT struct {
+	// Token is issuer-signed JWT
+	Token       string
+	Disclosures []Disclosure
+	// KeyBinding is key binding JWT, it is empty when SD-JWT has no key binding
+	KeyBinding string
+}
+
+// Parse parses compact serialization <Token>~<Disclosure 1>~...~<Disclosure N>~[<KeyBinding>].
+func Parse(serialized string) (SDJWT, error) {
+	parts := strings.Split(serialized, separator)
+	if len(parts) < 2 || parts[0] == "" {
+		return SDJWT{}, ErrInvalidFormat
+	}
+
+	sdJWT := SDJWT{
+		Token:       parts[0],
+		Disclosures: make([]Disclosure, 0, len(parts)-2),
+		KeyBinding:  parts[len(parts)-1],
+	}
+	for _, encoded := range parts[1 : len(parts)-1] {
+		if encoded == "" {
+			return SDJWT{}, ErrInvalidFormat
+		}
+		disclosure, err := ParseDisclosure(encoded)
+		if err != nil {
+			return SDJWT{}, err
+		}
+		sdJWT.Disclosures = append(sdJWT.Disclosures, disclosure)
+	}
+	return sdJWT, nil
+}
+
+// String returns compact serialization of SD-JWT.
+func (s SDJWT) String() string {
+	return s.withoutKeyBinding() + s.KeyBinding
+}
+
+// withoutKeyBinding returns serialization without key binding JWT, its hash is sd_hash claim of key binding JWT.
+func (s SDJWT) withoutKeyBinding() string {
+	var builder strings.Builder
+	builder.WriteString(s.Token)
+	builder.WriteString(separator)
+	for _, disclosure := range s.Disclosures {
+		builder.WriteString(disclosure.String())
+		builder.WriteString(separator)
+	}
+	return builder.String()
+}
+
+// HashAlgName returns _sd_alg claim value of hash, see IANA Named Information Hash Algorithm Registry.
+func HashAlgName(hash crypto.Hash) (string, error) {
+	switch hash {
+	case crypto.SHA256:
+		return "sha-256", nil
+	case crypto.SHA384:
+		return "sha-384", nil
+	case crypto.SHA512:
+		return "sha-512", nil
+	default:
+		return "", ErrUnsupportedHashAlg
+	}
+}
+
+// payloadHash returns hash of _sd_alg claim, sha-256 is used when claim is absent.
+func payloadHash(payload jwt.Payload) (crypto.Hash, error) {
+	alg, ok := payload[sdAlgClaim]
+	if !ok {
+		return crypto.SHA256, nil
+	}
+	switch alg {
+	case "sha-256":
+		return crypto.SHA256, nil
+	case "sha-384":
+		return crypto.SHA384, nil
+	case "sha-512":
+		return crypto.SHA512, nil
+	default:
+		return 0, ErrUnsupportedHashAlg
+	}
+}
+
+// unverifiedPayload decodes payload of issuer-signed JWT without signature verification, it is used by holder.
+func unverifiedPayload(token string) (jwt.Payload, error) {
+	rawToken, err := jwt.ParseRawToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload jwt.Payload
+	data, err := base64.RawURLEncoding.DecodeString(rawToken.Payload())
+	if err == nil {
+		err = json.Unmarshal(data, &payload)
+	}
+	if err != nil {
+		return nil, &jwt.ParseError{
+			Stage:   jwt.StageDecodePayload,
+			Segment: jwt.PayloadSegment,
+			Err:     fmt.Errorf("%w: %w", jwt.ErrBadToken, err),
+		}
+	}
+	return payload, nil
+}
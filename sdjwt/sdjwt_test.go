@@ -0,0 +1,505 @@
+package sdjwt_test
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/sdjwt"
+	"gotest.tools/v3/assert"
+)
+
+// disclosures of draft-ietf-oauth-selective-disclosure-jwt section 5.1 example
+const (
+	givenNameDisclosure           = "WyIyR0xDNDJzS1F2ZUNmR2ZyeU5STjl3IiwgImdpdmVuX25hbWUiLCAiSm9obiJd"
+	familyNameDisclosure          = "WyJlbHVWNU9nM2dTTklJOEVZbnN4QV9BIiwgImZhbWlseV9uYW1lIiwgIkRvZSJd"
+	emailDisclosure               = "WyI2SWo3dE0tYTVpVlBHYm9TNXRtdlZBIiwgImVtYWlsIiwgImpvaG5kb2VAZXhhbXBsZS5jb20iXQ"
+	phoneNumberDisclosure         = "WyJlSThaV205UW5LUHBOUGVOZW5IZGhRIiwgInBob25lX251bWJlciIsICIrMS0yMDItNTU1LTAxMDEiXQ"
+	phoneNumberVerifiedDisclosure = "WyJRZ19PNjR6cUF4ZTQxMmExMDhpcm9BIiwgInBob25lX251bWJlcl92ZXJpZmllZCIsIHRydWVd"
+	addressDisclosure             = "WyJBSngtMDk1VlBycFR0TjRRTU9xUk9BIiwgImFkZHJlc3MiLCB7InN0cmVldF9hZGRyZXNzIjogIjEyMyBNYWluIFN0IiwgImxvY2FsaXR5IjogIkFueXRvd24iLCAicmVnaW9uIjogIkFueXN0YXRlIiwgImNvdW50cnkiOiAiVVMifV0"
+	birthdateDisclosure           = "WyJQYzMzSk0yTGNoY1VfbEhnZ3ZfdWZRIiwgImJpcnRoZGF0ZSIsICIxOTQwLTAxLTAxIl0"
+	updatedAtDisclosure           = "WyJHMDJOU3JRZmpGWFE3SW8wOXN5YWpBIiwgInVwZGF0ZWRfYXQiLCAxNTcwMDAwMDAwXQ"
+	usDisclosure                  = "WyJsa2x4RjVqTVlsR1RQVW92TU5JdkNBIiwgIlVTIl0"
+	deDisclosure                  = "WyJuUHVvUW5rUkZxM0JJZUFtN0FuWEZBIiwgIkRFIl0"
+)
+
+// examplePayload returns issuer payload of draft-ietf-oauth-selective-disclosure-jwt section 5.1 example.
+func examplePayload() jwt.Payload {
+	return jwt.Payload{
+		"_sd": []any{
+			"CrQe7S5kqBAHt-nMYXgc6bdt2SH5aTY1sU_M-PgkjPI",
+			"JzYjH4svliH0R3PyEMfeZu6Jt69u5qehZo7F7EPYlSE",
+			"PorFbpKuVu6xymJagvkFsFXAbRoc2JGlAUA2BA4o7cI",
+			"TGf4oLbgwd5JQaHyKVQZU9UdGE0w5rtDsrZzfUaomLo",
+			"XQ_3kPKt1XyX7KANkqVR6yZ2Va5NrPIvPYbyMvRKBMM",
+			"XzFrzwscM6Gn6CJDc6vVK8BkMnfG8vOSKfpPIZdAfdE",
+			"gbOsI4Edq2x2Kw-w5wPEzakob9hV1cRD0ATN3oQL9JM",
+			"jsu9yVulwQQlhFlM_3JlzMaSFzglhQG0DpfayQwLUK4",
+		},
+		"iss": "https://issuer.example.com",
+		"iat": 1683000000,
+		"exp": 1883000000,
+		"sub": "user_42",
+		"nationalities": []any{
+			map[string]any{"...": "pFndjkZ_VCzmyTa6UjlZo3dh-ko8aIKQc9DlGzhaVYo"},
+			map[string]any{"...": "7Cf6JkPudry3lcbwHgeZ8khAv1U1OSlerP0VkBJrWZ0"},
+		},
+		"_sd_alg": "sha-256",
+	}
+}
+
+// exampleToken signs payload as is, jwttest tokens have jti claim that is absent in the example.
+func exampleToken(t *testing.T, keys *jwttest.Issuer, payload jwt.Payload) string {
+	token, err := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod()).CreateToken(payload)
+	assert.NilError(t, err)
+	return token
+}
+
+func Test_Disclosure(t *testing.T) {
+	// draft-ietf-oauth-selective-disclosure-jwt section 4.2.1 and 4.2.2 examples
+	disclosure, err := sdjwt.ParseDisclosure("WyJfMjZiYzRMVC1hYzZxMktJNmNCVzVlcyIsICJmYW1pbHlfbmFtZSIsICJNw7ZiaXVzIl0")
+	assert.NilError(t, err)
+	assert.Equal(t, disclosure.Salt, "_26bc4LT-ac6q2KI6cBW5es")
+	assert.Equal(t, disclosure.Name, "family_name")
+	assert.Equal(t, disclosure.Value, "Möbius")
+	assert.Assert(t, !disclosure.ArrayElement())
+	assert.Equal(t, disclosure.Digest(crypto.SHA256), "X9yH0Ajrdm1Oij4tWso9UzzKJvPoDxwmuEcO3XAdRC0")
+
+	disclosure, err = sdjwt.ParseDisclosure("WyJsa2x4RjVqTVlsR1RQVW92TU5JdkNBIiwgIkZSIl0")
+	assert.NilError(t, err)
+	assert.Equal(t, disclosure.Salt, "lklxF5jMYlGTPUovMNIvCA")
+	assert.Equal(t, disclosure.Value, "FR")
+	assert.Assert(t, disclosure.ArrayElement())
+	assert.Equal(t, disclosure.Digest(crypto.SHA256), "w0I8EKcdCtUPkGCNUrfwVp2xEgNjtoIDlOxc9-PlOhs")
+
+	disclosure, err = sdjwt.NewDisclosure("given_name", "John")
+	assert.NilError(t, err)
+	parsed, err := sdjwt.ParseDisclosure(disclosure.String())
+	assert.NilError(t, err)
+	assert.Equal(t, parsed.Salt, disclosure.Salt)
+	assert.Equal(t, len(parsed.Salt), 22)
+	assert.Equal(t, parsed.Name, "given_name")
+	assert.Equal(t, parsed.Value, "John")
+	assert.Equal(t, parsed.Digest(crypto.SHA256), disclosure.Digest(crypto.SHA256))
+
+	_, err = sdjwt.NewDisclosure("_sd", "value")
+	assert.ErrorIs(t, err, sdjwt.ErrReservedClaimName)
+
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	for _, encoded := range []string{
+		"not base64!",
+		encode(`{"salt":"name"}`),
+		encode(`["salt"]`),
+		encode(`["salt", "name", "value", "extra"]`),
+		encode(`[1, "name", "value"]`),
+		encode(`["salt", 1, "value"]`),
+		encode(`["salt", "...", "value"]`),
+		encode(`["salt", "_sd", ["digest"]]`),
+	} {
+		_, err = sdjwt.ParseDisclosure(encoded)
+		assert.ErrorIs(t, err, sdjwt.ErrInvalidDisclosure, encoded)
+	}
+}
+
+func Test_Verifier_Example(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	verifier := sdjwt.NewVerifier(keys.Parser())
+	token := exampleToken(t, keys, examplePayload())
+
+	presentation := strings.Join([]string{
+		token, givenNameDisclosure, familyNameDisclosure, emailDisclosure, phoneNumberDisclosure, phoneNumberVerifiedDisclosure,
+		addressDisclosure, birthdateDisclosure, updatedAtDisclosure, usDisclosure, deDisclosure, "",
+	}, "~")
+
+	verified, err := verifier.Verify(ctx, presentation)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, verified.Payload, jwt.Payload{
+		"iss":                   "https://issuer.example.com",
+		"iat":                   float64(1683000000),
+		"exp":                   float64(1883000000),
+		"sub":                   "user_42",
+		"nationalities":         []any{"US", "DE"},
+		"given_name":            "John",
+		"family_name":           "Doe",
+		"email":                 "johndoe@example.com",
+		"phone_number":          "+1-202-555-0101",
+		"phone_number_verified": true,
+		"address": map[string]any{
+			"street_address": "123 Main St",
+			"locality":       "Anytown",
+			"region":         "Anystate",
+			"country":        "US",
+		},
+		"birthdate":  "1940-01-01",
+		"updated_at": float64(1570000000),
+	})
+
+	presentation = strings.Join([]string{token, familyNameDisclosure, addressDisclosure, deDisclosure, ""}, "~")
+	verified, err = verifier.Verify(ctx, presentation)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, verified.Payload, jwt.Payload{
+		"iss":           "https://issuer.example.com",
+		"iat":           float64(1683000000),
+		"exp":           float64(1883000000),
+		"sub":           "user_42",
+		"nationalities": []any{"DE"},
+		"family_name":   "Doe",
+		"address": map[string]any{
+			"street_address": "123 Main St",
+			"locality":       "Anytown",
+			"region":         "Anystate",
+			"country":        "US",
+		},
+	})
+}
+
+func Test_Verifier_Errors(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	verifier := sdjwt.NewVerifier(keys.Parser())
+	token := exampleToken(t, keys, examplePayload())
+
+	payload := examplePayload()
+	payload["_sd"] = append(payload["_sd"].([]any), "jsu9yVulwQQlhFlM_3JlzMaSFzglhQG0DpfayQwLUK4")
+	duplicateDigestToken := keys.Token(payload)
+
+	payload = examplePayload()
+	payload["given_name"] = "Jane"
+	conflictToken := keys.Token(payload)
+
+	payload = examplePayload()
+	payload["_sd_alg"] = "md5"
+	md5Token := keys.Token(payload)
+
+	payload = examplePayload()
+	payload["_sd"] = "jsu9yVulwQQlhFlM_3JlzMaSFzglhQG0DpfayQwLUK4"
+	sdNotArrayToken := keys.Token(payload)
+
+	payload = examplePayload()
+	payload["nationalities"] = []any{map[string]any{"...": "jsu9yVulwQQlhFlM_3JlzMaSFzglhQG0DpfayQwLUK4"}}
+	payload["_sd"] = []any{}
+	claimInArrayToken := keys.Token(payload)
+
+	payload = examplePayload()
+	payload["_sd"] = []any{"pFndjkZ_VCzmyTa6UjlZo3dh-ko8aIKQc9DlGzhaVYo"}
+	delete(payload, "nationalities")
+	elementInObjectToken := keys.Token(payload)
+
+	cases := []struct {
+		name        string
+		parts       []string
+		expectedErr error
+	}{
+		{
+			name:        "no separator",
+			parts:       []string{token},
+			expectedErr: sdjwt.ErrInvalidFormat,
+		},
+		{
+			name:        "empty disclosure",
+			parts:       []string{token, givenNameDisclosure, "", ""},
+			expectedErr: sdjwt.ErrInvalidFormat,
+		},
+		{
+			name:        "unreferenced disclosure",
+			parts:       []string{token, "WyJfMjZiYzRMVC1hYzZxMktJNmNCVzVlcyIsICJmYW1pbHlfbmFtZSIsICJNw7ZiaXVzIl0", ""},
+			expectedErr: sdjwt.ErrUnreferencedDisclosure,
+		},
+		{
+			name:        "repeated disclosure",
+			parts:       []string{token, givenNameDisclosure, givenNameDisclosure, ""},
+			expectedErr: sdjwt.ErrDuplicateDigest,
+		},
+		{
+			name:        "repeated digest",
+			parts:       []string{duplicateDigestToken, ""},
+			expectedErr: sdjwt.ErrDuplicateDigest,
+		},
+		{
+			name:        "disclosed claim exists",
+			parts:       []string{conflictToken, givenNameDisclosure, ""},
+			expectedErr: sdjwt.ErrInvalidDisclosure,
+		},
+		{
+			name:        "claim disclosure in array",
+			parts:       []string{claimInArrayToken, givenNameDisclosure, ""},
+			expectedErr: sdjwt.ErrInvalidDisclosure,
+		},
+		{
+			name:        "array element disclosure in object",
+			parts:       []string{elementInObjectToken, usDisclosure, ""},
+			expectedErr: sdjwt.ErrInvalidDisclosure,
+		},
+		{
+			name:        "unsupported hash",
+			parts:       []string{md5Token, ""},
+			expectedErr: sdjwt.ErrUnsupportedHashAlg,
+		},
+		{
+			name:        "_sd is not array",
+			parts:       []string{sdNotArrayToken, ""},
+			expectedErr: sdjwt.ErrInvalidFormat,
+		},
+		{
+			name:        "wrong sign",
+			parts:       []string{keys.WrongSignatureToken(examplePayload()), givenNameDisclosure, ""},
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := verifier.Verify(ctx, strings.Join(cs.parts, "~"))
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+	}
+}
+
+func Test_Issuer(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod(),
+		jwt.WithHeaderType("dc+sd-jwt"),
+		jwt.WithIssuer("https://issuer.example.com"),
+		jwt.WithTTL(time.Hour),
+	)
+	issuer := sdjwt.NewIssuer(creator, sdjwt.WithDecoys(2))
+	verifier := sdjwt.NewVerifier(keys.Parser(), sdjwt.WithVerifierClock(jwt.SystemClock))
+
+	sdJWT, err := issuer.Issue(ctx, jwt.Payload{
+		"sub":         "user",
+		"given_name":  sdjwt.Disclosable{Value: "John"},
+		"family_name": sdjwt.Disclosable{Value: "Doe"},
+		"address": sdjwt.Disclosable{Value: map[string]any{
+			"street_address": sdjwt.Disclosable{Value: "Schulstr. 12"},
+			"country":        "DE",
+		}},
+		"nationalities": []any{sdjwt.Disclosable{Value: "US"}, "DE"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(sdJWT.Disclosures), 5)
+	assert.Equal(t, sdJWT.KeyBinding, "")
+
+	issued, err := keys.Parser().ParseToken(sdJWT.Token)
+	assert.NilError(t, err)
+	assert.Equal(t, issued.Header, jwt.Header{Type: "dc+sd-jwt", Alg: "ES256"})
+	assert.Equal(t, issued.Payload["_sd_alg"], "sha-256")
+	assert.Equal(t, len(issued.Payload["_sd"].([]any)), 5)
+	for _, name := range []string{"given_name", "family_name", "address"} {
+		_, ok := issued.Payload[name]
+		assert.Assert(t, !ok, name)
+	}
+	nationalities := issued.Payload["nationalities"].([]any)
+	assert.Equal(t, nationalities[1], "DE")
+	_, ok := nationalities[0].(map[string]any)["..."]
+	assert.Assert(t, ok)
+	// serialized SD-JWT without disclosures reveals nothing
+	assert.Assert(t, !strings.Contains(sdJWT.Token, base64.RawURLEncoding.EncodeToString([]byte("John"))))
+
+	parsed, err := sdjwt.Parse(sdJWT.String())
+	assert.NilError(t, err)
+	assert.Equal(t, parsed.String(), sdJWT.String())
+
+	verified, err := verifier.Verify(ctx, sdJWT.String(), sdjwt.WithValidators(jwt.VerifyTokenExpiration))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, verified.Payload, jwt.Payload{
+		"iss":         "https://issuer.example.com",
+		"iat":         verified.Payload["iat"],
+		"exp":         verified.Payload["exp"],
+		"jti":         verified.Payload["jti"],
+		"sub":         "user",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"address": map[string]any{
+			"street_address": "Schulstr. 12",
+			"country":        "DE",
+		},
+		"nationalities": []any{"US", "DE"},
+	})
+
+	selected, err := sdJWT.Select("/address/street_address", "/nationalities/1")
+	assert.NilError(t, err)
+	assert.Equal(t, len(selected.Disclosures), 2)
+	verified, err = verifier.Verify(ctx, selected.String())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, verified.Payload["address"], map[string]any{"street_address": "Schulstr. 12", "country": "DE"})
+	assert.DeepEqual(t, verified.Payload["nationalities"], []any{"DE"})
+	_, ok = verified.Payload["given_name"]
+	assert.Assert(t, !ok)
+
+	selected, err = sdJWT.Select("/address")
+	assert.NilError(t, err)
+	verified, err = verifier.Verify(ctx, selected.String())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, verified.Payload["address"], map[string]any{"country": "DE"})
+
+	_, err = issuer.Issue(ctx, jwt.Payload{"_sd": []any{}})
+	assert.ErrorIs(t, err, sdjwt.ErrReservedClaimName)
+	_, err = sdjwt.NewIssuer(creator, sdjwt.WithHashAlg(crypto.MD5)).Issue(ctx, jwt.Payload{"sub": "user"})
+	assert.ErrorIs(t, err, sdjwt.ErrUnsupportedHashAlg)
+
+	sdJWT, err = sdjwt.NewIssuer(creator, sdjwt.WithHashAlg(crypto.SHA512)).Issue(ctx, jwt.Payload{"email": sdjwt.Disclosable{Value: "user@example.com"}})
+	assert.NilError(t, err)
+	verified, err = verifier.Verify(ctx, sdJWT.String())
+	assert.NilError(t, err)
+	assert.Equal(t, verified.Payload["email"], "user@example.com")
+}
+
+func Test_KeyBinding(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+	holderKeys := jwttest.NewIssuer(t)
+	holderKey, err := jwk.NewECPublicKey(holderKeys.PublicKey())
+	assert.NilError(t, err)
+
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod(), jwt.WithHeaderType("dc+sd-jwt"))
+	issued, err := sdjwt.NewIssuer(creator).Issue(ctx,
+		jwt.Payload{"given_name": sdjwt.Disclosable{Value: "John"}, "family_name": sdjwt.Disclosable{Value: "Doe"}},
+		sdjwt.WithHolderKey(holderKey),
+	)
+	assert.NilError(t, err)
+	sdJWT, err := issued.Select("/given_name")
+	assert.NilError(t, err)
+
+	const verifierURL = "https://verifier.example.org"
+	verifier := sdjwt.NewVerifier(keys.Parser(),
+		sdjwt.RequireKeyBinding(verifierURL),
+		sdjwt.WithVerifierClock(jwt.ClockFunc(func() time.Time { return now })),
+	)
+	holder := sdjwt.NewHolder(holderKeys.SigningMethod(), sdjwt.WithClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	presentation, err := holder.Present(ctx, sdJWT, verifierURL, "nonce")
+	assert.NilError(t, err)
+	parsed, err := sdjwt.Parse(presentation)
+	assert.NilError(t, err)
+	kb, err := holderKeys.Parser().ParseToken(parsed.KeyBinding)
+	assert.NilError(t, err)
+	assert.Equal(t, kb.Header, jwt.Header{Type: "kb+jwt", Alg: "ES256"})
+	sdHash := sha256Digest(sdJWT.String())
+	assert.DeepEqual(t, kb.Payload, jwt.Payload{
+		"iat":     float64(now.Unix()),
+		"aud":     verifierURL,
+		"nonce":   "nonce",
+		"sd_hash": sdHash,
+	})
+
+	verified, err := verifier.Verify(ctx, presentation, sdjwt.WithNonce("nonce"))
+	assert.NilError(t, err)
+	assert.Equal(t, verified.Payload["given_name"], "John")
+	assert.DeepEqual(t, verified.Payload["cnf"], map[string]any{"jwk": map[string]any{
+		"kty": "EC", "crv": "P-256", "x": holderKey.X, "y": holderKey.Y,
+	}})
+
+	present := func(holder *sdjwt.Holder, sdJWT sdjwt.SDJWT, audience, nonce string) string {
+		presentation, err := holder.Present(ctx, sdJWT, audience, nonce)
+		assert.NilError(t, err)
+		return presentation
+	}
+	otherHolderKeys := jwttest.NewIssuer(t)
+	noKeyToken, err := sdjwt.NewIssuer(creator).Issue(ctx, jwt.Payload{"given_name": sdjwt.Disclosable{Value: "John"}})
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name         string
+		presentation string
+		nonce        string
+		expectedErr  error
+	}{
+		{
+			name:         "no key binding",
+			presentation: sdJWT.String(),
+			expectedErr:  sdjwt.ErrKeyBindingRequired,
+		},
+		{
+			name:         "other nonce",
+			presentation: presentation,
+			nonce:        "other",
+			expectedErr:  sdjwt.ErrInvalidNonce,
+		},
+		{
+			name:         "other audience",
+			presentation: present(holder, sdJWT, "https://other.example.org", "nonce"),
+			nonce:        "nonce",
+			expectedErr:  sdjwt.ErrInvalidAudience,
+		},
+		{
+			name:         "other holder key",
+			presentation: present(sdjwt.NewHolder(otherHolderKeys.SigningMethod()), sdJWT, verifierURL, "nonce"),
+			nonce:        "nonce",
+			expectedErr:  jwt.ErrSignNotVerified,
+		},
+		{
+			name: "expired",
+			presentation: present(
+				sdjwt.NewHolder(holderKeys.SigningMethod(), sdjwt.WithClock(jwt.ClockFunc(func() time.Time { return now.Add(-time.Hour) }))),
+				sdJWT, verifierURL, "nonce",
+			),
+			nonce:       "nonce",
+			expectedErr: sdjwt.ErrKeyBindingExpired,
+		},
+		{
+			name: "issued in future",
+			presentation: present(
+				sdjwt.NewHolder(holderKeys.SigningMethod(), sdjwt.WithClock(jwt.ClockFunc(func() time.Time { return now.Add(time.Minute) }))),
+				sdJWT, verifierURL, "nonce",
+			),
+			nonce:       "nonce",
+			expectedErr: sdjwt.ErrKeyBindingExpired,
+		},
+		{
+			name:         "disclosures changed after key binding",
+			presentation: sdjwt.SDJWT{Token: issued.Token, Disclosures: issued.Disclosures, KeyBinding: parsed.KeyBinding}.String(),
+			nonce:        "nonce",
+			expectedErr:  sdjwt.ErrSDHashMismatch,
+		},
+		{
+			name:         "no cnf claim",
+			presentation: present(holder, noKeyToken, verifierURL, "nonce"),
+			nonce:        "nonce",
+			expectedErr:  sdjwt.ErrInvalidKeyBinding,
+		},
+		{
+			name:         "issuer-signed JWT as key binding",
+			presentation: sdJWT.String() + holderKeys.Token(jwt.Payload{"aud": verifierURL, "nonce": "nonce"}),
+			nonce:        "nonce",
+			expectedErr:  sdjwt.ErrInvalidKeyBinding,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := verifier.Verify(ctx, cs.presentation, sdjwt.WithNonce(cs.nonce))
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+	}
+
+	// key binding JWT is checked even when it is not required
+	optionalVerifier := sdjwt.NewVerifier(keys.Parser(),
+		sdjwt.WithAudience(verifierURL),
+		sdjwt.WithVerifierClock(jwt.ClockFunc(func() time.Time { return now })),
+	)
+	_, err = optionalVerifier.Verify(ctx, presentation, sdjwt.WithNonce("nonce"))
+	assert.NilError(t, err)
+	_, err = optionalVerifier.Verify(ctx, sdJWT.String())
+	assert.NilError(t, err)
+	_, err = optionalVerifier.Verify(ctx, present(holder, sdJWT, "https://other.example.org", "nonce"), sdjwt.WithNonce("nonce"))
+	assert.ErrorIs(t, err, sdjwt.ErrInvalidAudience)
+
+	// aud and nonce of key binding JWT are not accepted without expected values
+	_, err = verifier.Verify(ctx, presentation)
+	assert.ErrorIs(t, err, sdjwt.ErrNonceRequired)
+	_, err = sdjwt.NewVerifier(keys.Parser()).Verify(ctx, presentation, sdjwt.WithNonce("nonce"))
+	assert.ErrorIs(t, err, sdjwt.ErrAudienceRequired)
+}
+
+func sha256Digest(value string) string {
+	h := crypto.SHA256.New()
+	h.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
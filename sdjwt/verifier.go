@@ -0,0 +1,245 @@
+package sdjwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwk"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var (
+	ErrKeyBindingRequired jwt.TokenInvalidError = "key_binding_required"
+	ErrInvalidKeyBinding  jwt.TokenInvalidError = "invalid_key_binding"
+	ErrKeyBindingExpired  jwt.TokenInvalidError = "key_binding_expired"
+	ErrInvalidAudience    jwt.TokenInvalidError = "invalid_audience"
+	ErrInvalidNonce       jwt.TokenInvalidError = "invalid_nonce"
+	ErrSDHashMismatch     jwt.TokenInvalidError = "sd_hash_mismatch"
+)
+
+var (
+	ErrAudienceRequired = errors.New("audience is required to verify key binding JWT")
+	ErrNonceRequired    = errors.New("nonce is required to verify key binding JWT")
+)
+
+const (
+	// DefaultKeyBindingLifetime is how long key binding JWT is accepted after its iat.
+	DefaultKeyBindingLifetime = 5 * time.Minute
+	// keyBindingFutureLeeway accepts key binding JWTs with iat slightly in the future because of holder clock skew.
+	keyBindingFutureLeeway = 5 * time.Second
+)
+
+type VerifierOption func(v *Verifier)
+
+// RequireKeyBinding rejects presentations without key binding JWT, aud claim of key binding JWT must be audience.
+func RequireKeyBinding(audience string) VerifierOption {
+	return func(v *Verifier) {
+		v.keyBinding = true
+		v.audience = audience
+	}
+}
+
+// WithAudience sets audience of key binding JWT without requiring key binding.
+// Presentation with key binding JWT is rejected with ErrAudienceRequired when audience is not set.
+func WithAudience(audience string) VerifierOption {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// WithKeyBindingLifetime sets how long key binding JWT is accepted after its iat.
+func WithKeyBindingLifetime(lifetime time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.keyBindingLifetime = lifetime
+	}
+}
+
+// WithVerifierClock sets clock used to check iat claim of key binding JWT.
+func WithVerifierClock(clock jwt.Clock) VerifierOption {
+	return func(v *Verifier) {
+		v.clock = clock
+	}
+}
+
+// Verifier verifies SD-JWT presentations, see draft-ietf-oauth-selective-disclosure-jwt section 7.
+type Verifier struct {
+	parser             jwt.ContextTokenParser
+	keyBinding         bool
+	audience           string
+	keyBindingLifetime time.Duration
+	clock              jwt.Clock
+}
+
+// NewVerifier returns verifier of presentations, issuer-signed JWT is verified by parser.
+func NewVerifier(parser jwt.ContextTokenParser, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		parser:             parser,
+		keyBindingLifetime: DefaultKeyBindingLifetime,
+		clock:              jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+type verifyConfig struct {
+	nonce      string
+	validators []jwt.TokenValidator
+}
+
+type VerifyOption func(c *verifyConfig)
+
+// WithNonce requires nonce claim of key binding JWT to be nonce.
+// Presentation with key binding JWT is rejected with ErrNonceRequired when nonce is not set.
+func WithNonce(nonce string) VerifyOption {
+	return func(c *verifyConfig) {
+		c.nonce = nonce
+	}
+}
+
+// WithValidators sets validators applied to rebuilt payload, e.g. expiration check.
+func WithValidators(validators ...jwt.TokenValidator) VerifyOption {
+	return func(c *verifyConfig) {
+		c.validators = validators
+	}
+}
+
+// Verify verifies issuer-signed JWT and key binding JWT of presentation and returns token with disclosed claims,
+// _sd and _sd_alg claims and digests of undisclosed values are removed from the payload.
+func (v *Verifier) Verify(ctx context.Context, presentation string, opts ...VerifyOption) (jwt.Token, error) {
+	var config verifyConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	sdJWT, err := Parse(presentation)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+
+	token, err := v.parser.ParseTokenContext(ctx, sdJWT.Token)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+	hash, err := payloadHash(token.Payload)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+	token.Payload, _, err = disclose(token.Payload, hash, sdJWT.Disclosures)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+
+	switch {
+	case sdJWT.KeyBinding != "":
+		err = v.verifyKeyBinding(ctx, sdJWT, token, digest(hash, sdJWT.withoutKeyBinding()), config.nonce)
+	case v.keyBinding:
+		err = ErrKeyBindingRequired
+	}
+	if err != nil {
+		return jwt.Token{}, err
+	}
+
+	err = jwt.ValidateTokenContext(ctx, token, config.validators...)
+	if err != nil {
+		return jwt.Token{}, err
+	}
+	return token, nil
+}
+
+func (v *Verifier) verifyKeyBinding(ctx context.Context, sdJWT SDJWT, token jwt.Token, sdHash, nonce string) error {
+	// key binding JWT is replayable to any verifier unless aud and nonce are checked against expected values
+	if v.audience == "" {
+		return ErrAudienceRequired
+	}
+	if nonce == "" {
+		return ErrNonceRequired
+	}
+
+	key, ok := confirmationKey(token)
+	if !ok {
+		return ErrInvalidKeyBinding
+	}
+	public, err := key.PublicKey()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKeyBinding, err)
+	}
+	header, err := decodeHeader(sdJWT.KeyBinding)
+	if err != nil {
+		return err
+	}
+	if header.Type != KeyBindingType {
+		return ErrInvalidKeyBinding
+	}
+	signingMethod, err := signingmethods.NewVerifier(header.Alg, public)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKeyBinding, err)
+	}
+
+	keyBinding, err := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod).ParseTokenContext(ctx, sdJWT.KeyBinding)
+	if err != nil {
+		return err
+	}
+
+	issuedAt, ok := jwt.PayloadTime(keyBinding.Payload, "iat")
+	if !ok {
+		return ErrInvalidKeyBinding
+	}
+	now := v.clock.Now()
+	if now.Sub(issuedAt) > v.keyBindingLifetime || issuedAt.Sub(now) > keyBindingFutureLeeway {
+		return ErrKeyBindingExpired
+	}
+
+	if aud, _ := keyBinding.Payload["aud"].(string); aud != v.audience {
+		return ErrInvalidAudience
+	}
+	if kbNonce, _ := keyBinding.Payload["nonce"].(string); kbNonce != nonce {
+		return ErrInvalidNonce
+	}
+	if kbSDHash, _ := keyBinding.Payload["sd_hash"].(string); kbSDHash != sdHash {
+		return ErrSDHashMismatch
+	}
+	return nil
+}
+
+// confirmationKey returns cnf.jwk claim of issuer-signed JWT.
+func confirmationKey(token jwt.Token) (jwk.Key, bool) {
+	cnf, ok := token.Payload["cnf"].(map[string]any)
+	if !ok {
+		return jwk.Key{}, false
+	}
+	data, err := json.Marshal(cnf["jwk"])
+	if err != nil {
+		return jwk.Key{}, false
+	}
+	var key jwk.Key
+	err = json.Unmarshal(data, &key)
+	return key, err == nil && key.Kty != ""
+}
+
+func decodeHeader(token string) (jwt.Header, error) {
+	rawToken, err := jwt.ParseRawToken(token)
+	if err != nil {
+		return jwt.Header{}, err
+	}
+
+	var header jwt.Header
+	data, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	if err == nil {
+		err = json.Unmarshal(data, &header)
+	}
+	if err != nil {
+		return jwt.Header{}, &jwt.ParseError{
+			Stage:   jwt.StageDecodeHeader,
+			Segment: jwt.HeaderSegment,
+			Err:     fmt.Errorf("%w: %w", jwt.ErrBadToken, err),
+		}
+	}
+	return header, nil
+}
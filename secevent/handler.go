@@ -0,0 +1,62 @@
+package secevent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// LogoutFunc logs out sessions of subject or session with sessionID, one of them could be empty.
+type LogoutFunc func(ctx context.Context, subject, sessionID string) error
+
+type logoutHandler struct {
+	parser *LogoutParser
+	logout LogoutFunc
+}
+
+// NewLogoutHandler returns back-channel logout endpoint of relying party, see OpenID Connect Back-Channel Logout 1.0 section 2.5.
+// Handler accepts POST request with logout_token form parameter and calls logout with sub and sid claims of the verified token.
+// It responds with 200 status on success and 400 status with JSON error when token is invalid or logout fails.
+func NewLogoutHandler(parser *LogoutParser, logout LogoutFunc) http.Handler {
+	return &logoutHandler{parser: parser, logout: logout}
+}
+
+func (h *logoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	logoutToken := r.PostFormValue("logout_token")
+	if logoutToken == "" {
+		badRequest(w, "invalid_request", "logout_token is required")
+		return
+	}
+
+	ctx := r.Context()
+	token, err := h.parser.Parse(ctx, logoutToken)
+	if err != nil {
+		badRequest(w, "invalid_request", "logout_token is invalid")
+		return
+	}
+
+	err = h.logout(ctx, token.Subject, token.SessionID)
+	if err != nil {
+		badRequest(w, "logout_failed", "logout failed")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func badRequest(w http.ResponseWriter, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{code, description})
+}
@@ -0,0 +1,99 @@
+package secevent
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+const (
+	// LogoutHeaderType is typ header of logout tokens, see OpenID Connect Back-Channel Logout 1.0 section 2.4.
+	LogoutHeaderType = "logout+jwt"
+	// BackChannelLogoutEvent is event type of logout tokens.
+	BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+	// DefaultLogoutTokenLifetime is lifetime of created logout tokens, the spec recommends at most two minutes.
+	DefaultLogoutTokenLifetime = 2 * time.Minute
+)
+
+var ErrNoSubjectOrSession jwt.TokenInvalidError = "no_subject_or_session"
+
+var ErrMissingSubjectAndSession = errors.New("subject or session id is required")
+
+// LogoutToken is parsed logout token, at least one of Subject and SessionID is set.
+type LogoutToken struct {
+	SecurityEventToken
+	SessionID string
+}
+
+// LogoutCreator creates logout tokens on OpenID provider.
+type LogoutCreator struct {
+	creator *Creator
+}
+
+// NewLogoutCreator returns creator of logout tokens, tokens expire after DefaultLogoutTokenLifetime unless WithTTL is passed.
+func NewLogoutCreator(signingMethod jwt.SigningMethod, issuer string, opts ...CreatorOption) *LogoutCreator {
+	opts = append([]CreatorOption{WithTTL(DefaultLogoutTokenLifetime)}, opts...)
+	return &LogoutCreator{creator: newCreator(signingMethod, LogoutHeaderType, issuer, opts...)}
+}
+
+// CreateLogoutToken creates logout token for client, subject or sessionID could be empty but not both.
+func (c *LogoutCreator) CreateLogoutToken(ctx context.Context, clientID, subject, sessionID string) (string, error) {
+	if subject == "" && sessionID == "" {
+		return "", ErrMissingSubjectAndSession
+	}
+
+	var claims jwt.Payload
+	if sessionID != "" {
+		claims = jwt.Payload{"sid": sessionID}
+	}
+
+	return c.creator.createToken(ctx,
+		SecurityEvent{
+			Subject:  subject,
+			Audience: []string{clientID},
+			Events:   []Event{{Type: BackChannelLogoutEvent}},
+		},
+		claims,
+	)
+}
+
+// LogoutParser parses logout tokens on relying party, see OpenID Connect Back-Channel Logout 1.0 section 2.6.
+// Token must have logout+jwt typ, exp claim, back-channel logout event and sub or sid claim.
+type LogoutParser struct {
+	parser *Parser
+}
+
+// NewLogoutParser returns parser of logout tokens verified by parser, issued by issuer for clientID.
+// WithReplayStore is recommended, logout tokens must be accepted only once.
+func NewLogoutParser(parser jwt.ContextTokenParser, issuer, clientID string, opts ...ParserOption) *LogoutParser {
+	opts = append(opts, WithAudience(clientID))
+	p := newParser(parser, LogoutHeaderType, issuer, opts...)
+	p.requireExp = true
+	return &LogoutParser{parser: p}
+}
+
+func (p *LogoutParser) Parse(ctx context.Context, token string) (LogoutToken, error) {
+	securityEventToken, err := p.parser.parse(ctx, token, jwt.TokenValidatorFunc(validateLogoutToken))
+	if err != nil {
+		return LogoutToken{}, err
+	}
+
+	sessionID, _ := securityEventToken.Token.Payload["sid"].(string)
+	return LogoutToken{SecurityEventToken: securityEventToken, SessionID: sessionID}, nil
+}
+
+func validateLogoutToken(token jwt.Token) error {
+	events, _ := token.Payload["events"].(map[string]any)
+	if _, ok := events[BackChannelLogoutEvent]; !ok {
+		return ErrInvalidEvents
+	}
+
+	sub, _ := token.Payload["sub"].(string)
+	sid, _ := token.Payload["sid"].(string)
+	if sub == "" && sid == "" {
+		return ErrNoSubjectOrSession
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package secevent_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/secevent"
+	"gotest.tools/v3/assert"
+)
+
+const clientID = "client"
+
+func Test_LogoutCreator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+
+	creator := secevent.NewLogoutCreator(keys.SigningMethod(), issuerURL,
+		secevent.WithClock(jwt.ClockFunc(func() time.Time { return now })),
+	)
+
+	logoutToken, err := creator.CreateLogoutToken(ctx, clientID, "user", "session")
+	assert.NilError(t, err)
+
+	token, err := keys.Parser().ParseToken(logoutToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Header, jwt.Header{Type: "logout+jwt", Alg: "ES256"})
+
+	jti := token.Payload["jti"]
+	assert.Assert(t, jti != "")
+	assert.DeepEqual(t, token.Payload, jwt.Payload{
+		"iss":    issuerURL,
+		"aud":    clientID,
+		"sub":    "user",
+		"sid":    "session",
+		"iat":    float64(now.Unix()),
+		"exp":    float64(now.Add(secevent.DefaultLogoutTokenLifetime).Unix()),
+		"jti":    jti,
+		"events": map[string]any{secevent.BackChannelLogoutEvent: map[string]any{}},
+	})
+
+	_, err = creator.CreateLogoutToken(ctx, clientID, "", "")
+	assert.ErrorIs(t, err, secevent.ErrMissingSubjectAndSession)
+}
+
+func Test_LogoutParser(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+	creator := secevent.NewLogoutCreator(keys.SigningMethod(), issuerURL)
+	parser := secevent.NewLogoutParser(keys.Parser(), issuerURL, clientID)
+
+	logoutCreator := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod(), jwt.WithHeaderType("logout+jwt"))
+	create := func(payload jwt.Payload) string {
+		claims := jwt.Payload{
+			"iss": issuerURL, "aud": clientID, "sub": "user", "jti": "id",
+			"iat": now.Unix(), "exp": now.Add(time.Minute).Unix(),
+			"events": map[string]any{secevent.BackChannelLogoutEvent: map[string]any{}},
+		}
+		for claim, value := range payload {
+			if value == nil {
+				delete(claims, claim)
+				continue
+			}
+			claims[claim] = value
+		}
+		logoutToken, err := logoutCreator.CreateToken(claims)
+		assert.NilError(t, err)
+		return logoutToken
+	}
+	createLogoutToken := func(subject, sessionID string) string {
+		logoutToken, err := creator.CreateLogoutToken(ctx, clientID, subject, sessionID)
+		assert.NilError(t, err)
+		return logoutToken
+	}
+
+	cases := []struct {
+		name              string
+		token             string
+		expectedSubject   string
+		expectedSessionID string
+		expectedErr       error
+	}{
+		{
+			name:              "subject and session",
+			token:             createLogoutToken("user", "session"),
+			expectedSubject:   "user",
+			expectedSessionID: "session",
+		},
+		{
+			name:            "subject only",
+			token:           createLogoutToken("user", ""),
+			expectedSubject: "user",
+		},
+		{
+			name:              "session only",
+			token:             createLogoutToken("", "session"),
+			expectedSessionID: "session",
+		},
+		{
+			name:        "no subject and session",
+			token:       create(jwt.Payload{"sub": nil}),
+			expectedErr: secevent.ErrNoSubjectOrSession,
+		},
+		{
+			name:        "nonce",
+			token:       create(jwt.Payload{"nonce": "n"}),
+			expectedErr: secevent.ErrNonceNotAllowed,
+		},
+		{
+			name:        "no logout event",
+			token:       create(jwt.Payload{"events": map[string]any{accountDisabled: map[string]any{}}}),
+			expectedErr: secevent.ErrInvalidEvents,
+		},
+		{
+			name:        "logout event is not object",
+			token:       create(jwt.Payload{"events": map[string]any{secevent.BackChannelLogoutEvent: true}}),
+			expectedErr: secevent.ErrInvalidEvents,
+		},
+		{
+			name:        "no exp",
+			token:       create(jwt.Payload{"exp": nil}),
+			expectedErr: jwt.ErrNoExpiration,
+		},
+		{
+			name:        "expired",
+			token:       create(jwt.Payload{"exp": now.Add(-time.Minute).Unix()}),
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:        "other client",
+			token:       create(jwt.Payload{"aud": "other"}),
+			expectedErr: secevent.ErrInvalidAudience,
+		},
+		{
+			name:        "other issuer",
+			token:       create(jwt.Payload{"iss": "https://evil.example.com"}),
+			expectedErr: secevent.ErrInvalidIssuer,
+		},
+		{
+			name: "security event token",
+			token: func() string {
+				setToken, err := secevent.NewCreator(keys.SigningMethod(), issuerURL, secevent.WithTTL(time.Minute)).
+					CreateToken(ctx, secevent.SecurityEvent{
+						Subject:  "user",
+						Audience: []string{clientID},
+						Events:   []secevent.Event{{Type: secevent.BackChannelLogoutEvent}},
+					})
+				assert.NilError(t, err)
+				return setToken
+			}(),
+			expectedErr: secevent.ErrInvalidType,
+		},
+		{
+			name:        "id token",
+			token:       keys.Token(jwt.Payload{"iss": issuerURL, "aud": clientID, "sub": "user", "nonce": "n"}),
+			expectedErr: secevent.ErrInvalidType,
+		},
+	}
+
+	for _, cs := range cases {
+		logoutToken, err := parser.Parse(ctx, cs.token)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr == nil {
+			assert.Equal(t, logoutToken.Subject, cs.expectedSubject, cs.name)
+			assert.Equal(t, logoutToken.SessionID, cs.expectedSessionID, cs.name)
+		}
+	}
+}
+
+func Test_LogoutHandler(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	creator := secevent.NewLogoutCreator(keys.SigningMethod(), issuerURL)
+	parser := secevent.NewLogoutParser(keys.Parser(), issuerURL, clientID,
+		secevent.WithReplayStore(jwt.NewMemoryReplayStore()),
+	)
+
+	type logout struct{ Subject, SessionID string }
+	var logouts []logout
+	errLogout := errors.New("logout failed")
+	handler := secevent.NewLogoutHandler(parser, func(ctx context.Context, subject, sessionID string) error {
+		if subject == "broken" {
+			return errLogout
+		}
+		logouts = append(logouts, logout{subject, sessionID})
+		return nil
+	})
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, receiverURL+"/backchannel-logout", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	createLogoutToken := func(subject, sessionID string) string {
+		logoutToken, err := creator.CreateLogoutToken(ctx, clientID, subject, sessionID)
+		assert.NilError(t, err)
+		return logoutToken
+	}
+	assertBadRequest := func(w *httptest.ResponseRecorder, expectedCode string) {
+		t.Helper()
+		assert.Equal(t, w.Code, http.StatusBadRequest)
+		assert.Equal(t, w.Header().Get("Cache-Control"), "no-store")
+		assert.Equal(t, w.Header().Get("Content-Type"), "application/json")
+		var body struct {
+			Error string `json:"error"`
+		}
+		assert.NilError(t, json.NewDecoder(w.Body).Decode(&body))
+		assert.Equal(t, body.Error, expectedCode)
+	}
+
+	logoutToken := createLogoutToken("user", "session")
+	w := post(url.Values{"logout_token": {logoutToken}})
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Header().Get("Cache-Control"), "no-store")
+	assert.DeepEqual(t, logouts, []logout{{"user", "session"}})
+
+	// logout token is accepted only once
+	assertBadRequest(post(url.Values{"logout_token": {logoutToken}}), "invalid_request")
+	assertBadRequest(post(url.Values{}), "invalid_request")
+	assertBadRequest(post(url.Values{"logout_token": {keys.WrongSignatureToken(jwt.Payload{"sub": "user"})}}), "invalid_request")
+	assertBadRequest(post(url.Values{"logout_token": {createLogoutToken("broken", "")}}), "logout_failed")
+
+	w = post(url.Values{"logout_token": {createLogoutToken("", "other")}})
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.DeepEqual(t, logouts, []logout{{"user", "session"}, {"", "other"}})
+
+	r := httptest.NewRequest(http.MethodGet, receiverURL+"/backchannel-logout?logout_token="+createLogoutToken("user", ""), nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, w.Code, http.StatusMethodNotAllowed)
+	assert.Equal(t, w.Header().Get("Allow"), http.MethodPost)
+	assert.Equal(t, len(logouts), 2)
+}
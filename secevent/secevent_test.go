@@ -0,0 +1,231 @@
+package secevent_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/jwttest"
+	"github.com/amidgo/jwt/secevent"
+	"gotest.tools/v3/assert"
+)
+
+const (
+	issuerURL   = "https://idp.example.com"
+	receiverURL = "https://rp.example.com"
+	// accountDisabled is event type of RISC, see OpenID RISC Event Types 1.0 section 2.3
+	accountDisabled = "https://schemas.openid.net/secevent/risc/event-type/account-disabled"
+)
+
+type accountDisabledEvent struct {
+	Subject struct {
+		Format string `json:"format"`
+		Email  string `json:"email"`
+	} `json:"subject"`
+	Reason string `json:"reason"`
+}
+
+func Test_Creator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+
+	creator := secevent.NewCreator(keys.SigningMethod(), issuerURL,
+		secevent.WithClock(jwt.ClockFunc(func() time.Time { return now })),
+	)
+
+	event := accountDisabledEvent{Reason: "hijacking"}
+	event.Subject.Format = "email"
+	event.Subject.Email = "user@example.com"
+	setToken, err := creator.CreateToken(ctx, secevent.SecurityEvent{
+		Audience:      []string{receiverURL},
+		TransactionID: "txn",
+		TimeOfEvent:   now.Add(-time.Minute),
+		Events:        []secevent.Event{{Type: accountDisabled, Payload: event}},
+	})
+	assert.NilError(t, err)
+
+	token, err := keys.Parser().ParseToken(setToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Header, jwt.Header{Type: "secevent+jwt", Alg: "ES256"})
+
+	jti := token.Payload["jti"]
+	assert.Assert(t, jti != "")
+	assert.DeepEqual(t, token.Payload, jwt.Payload{
+		"iss": issuerURL,
+		"aud": receiverURL,
+		"iat": float64(now.Unix()),
+		"jti": jti,
+		"txn": "txn",
+		"toe": float64(now.Add(-time.Minute).Unix()),
+		"events": map[string]any{
+			accountDisabled: map[string]any{
+				"subject": map[string]any{"format": "email", "email": "user@example.com"},
+				"reason":  "hijacking",
+			},
+		},
+	})
+
+	setToken, err = creator.CreateToken(ctx, secevent.SecurityEvent{Subject: "user", Events: []secevent.Event{{Type: accountDisabled}}})
+	assert.NilError(t, err)
+	token, err = keys.Parser().ParseToken(setToken)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token.Payload["events"], map[string]any{accountDisabled: map[string]any{}})
+	assert.Equal(t, token.Payload["sub"], "user")
+	_, ok := token.Payload["aud"]
+	assert.Assert(t, !ok)
+
+	_, err = creator.CreateToken(ctx, secevent.SecurityEvent{Subject: "user"})
+	assert.ErrorIs(t, err, secevent.ErrNoEvents)
+}
+
+func Test_Parser(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	keys := jwttest.NewIssuer(t)
+	creator := secevent.NewCreator(keys.SigningMethod(), issuerURL)
+	parser := secevent.NewParser(keys.Parser(), issuerURL, secevent.WithAudience(receiverURL))
+
+	event := accountDisabledEvent{Reason: "bulk-account"}
+	event.Subject.Format = "email"
+	event.Subject.Email = "user@example.com"
+	setToken, err := creator.CreateToken(ctx, secevent.SecurityEvent{
+		Subject:       "user",
+		Audience:      []string{receiverURL, "https://other.example.com"},
+		TransactionID: "txn",
+		TimeOfEvent:   now,
+		Events:        []secevent.Event{{Type: accountDisabled, Payload: event}},
+	})
+	assert.NilError(t, err)
+
+	set, err := parser.Parse(ctx, setToken)
+	assert.NilError(t, err)
+	assert.Equal(t, set.Issuer, issuerURL)
+	assert.DeepEqual(t, set.Audience, []string{receiverURL, "https://other.example.com"})
+	assert.Equal(t, set.Subject, "user")
+	assert.Equal(t, set.TransactionID, "txn")
+	assert.Equal(t, set.TimeOfEvent, now)
+	assert.Assert(t, set.ID != "")
+	assert.Assert(t, !set.IssuedAt.IsZero())
+
+	var parsedEvent accountDisabledEvent
+	assert.NilError(t, set.Event(accountDisabled, &parsedEvent))
+	assert.DeepEqual(t, parsedEvent, event)
+	assert.ErrorIs(t, set.Event(secevent.BackChannelLogoutEvent, &parsedEvent), secevent.ErrEventNotFound)
+
+	setCreator := jwt.NewTokenCreator(base64.RawURLEncoding, keys.SigningMethod(), jwt.WithHeaderType("application/SECEVENT+JWT"))
+	create := func(payload jwt.Payload) string {
+		claims := jwt.Payload{
+			"iss": issuerURL, "aud": receiverURL, "jti": "id", "iat": now.Unix(),
+			"events": map[string]any{accountDisabled: map[string]any{}},
+		}
+		for claim, value := range payload {
+			if value == nil {
+				delete(claims, claim)
+				continue
+			}
+			claims[claim] = value
+		}
+		setToken, err := setCreator.CreateToken(claims)
+		assert.NilError(t, err)
+		return setToken
+	}
+
+	cases := []struct {
+		name        string
+		token       string
+		expectedErr error
+	}{
+		{
+			name:  "media type typ",
+			token: create(nil),
+		},
+		{
+			name:  "not expired",
+			token: create(jwt.Payload{"exp": now.Add(time.Minute).Unix()}),
+		},
+		{
+			name:        "id token used as set",
+			token:       keys.Token(jwt.Payload{"iss": issuerURL, "aud": receiverURL, "jti": "id", "iat": now.Unix(), "events": map[string]any{accountDisabled: map[string]any{}}}),
+			expectedErr: secevent.ErrInvalidType,
+		},
+		{
+			name:        "nonce",
+			token:       create(jwt.Payload{"nonce": "n"}),
+			expectedErr: secevent.ErrNonceNotAllowed,
+		},
+		{
+			name:        "other issuer",
+			token:       create(jwt.Payload{"iss": "https://evil.example.com"}),
+			expectedErr: secevent.ErrInvalidIssuer,
+		},
+		{
+			name:        "other audience",
+			token:       create(jwt.Payload{"aud": []string{"https://other.example.com"}}),
+			expectedErr: secevent.ErrInvalidAudience,
+		},
+		{
+			name:        "no jti",
+			token:       create(jwt.Payload{"jti": nil}),
+			expectedErr: secevent.ErrMissingClaim,
+		},
+		{
+			name:        "no iat",
+			token:       create(jwt.Payload{"iat": nil}),
+			expectedErr: secevent.ErrMissingClaim,
+		},
+		{
+			name:        "expired",
+			token:       create(jwt.Payload{"exp": now.Add(-time.Minute).Unix()}),
+			expectedErr: jwt.ErrTokenExpired,
+		},
+		{
+			name:        "no events",
+			token:       create(jwt.Payload{"events": nil}),
+			expectedErr: secevent.ErrInvalidEvents,
+		},
+		{
+			name:        "empty events",
+			token:       create(jwt.Payload{"events": map[string]any{}}),
+			expectedErr: secevent.ErrInvalidEvents,
+		},
+		{
+			name:        "event is not object",
+			token:       create(jwt.Payload{"events": map[string]any{accountDisabled: "disabled"}}),
+			expectedErr: secevent.ErrInvalidEvents,
+		},
+		{
+			name:        "wrong sign",
+			token:       keys.WrongSignatureToken(jwt.Payload{"iss": issuerURL}),
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := parser.Parse(ctx, cs.token)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+	}
+}
+
+func Test_Parser_ReplayStore(t *testing.T) {
+	ctx := context.Background()
+	keys := jwttest.NewIssuer(t)
+	parser := secevent.NewParser(keys.Parser(), issuerURL, secevent.WithReplayStore(jwt.NewMemoryReplayStore()))
+
+	setToken, err := secevent.NewCreator(keys.SigningMethod(), issuerURL, secevent.WithTTL(time.Minute)).
+		CreateToken(ctx, secevent.SecurityEvent{Events: []secevent.Event{{Type: accountDisabled}}})
+	assert.NilError(t, err)
+
+	_, err = parser.Parse(ctx, setToken)
+	assert.NilError(t, err)
+	_, err = parser.Parse(ctx, setToken)
+	assert.ErrorIs(t, err, jwt.ErrTokenReplayed)
+
+	setToken, err = secevent.NewCreator(keys.SigningMethod(), issuerURL).
+		CreateToken(ctx, secevent.SecurityEvent{Events: []secevent.Event{{Type: accountDisabled}}})
+	assert.NilError(t, err)
+	_, err = parser.Parse(ctx, setToken)
+	assert.ErrorIs(t, err, jwt.ErrNoExpiration)
+}
@@ -0,0 +1,322 @@
+// Package secevent implements Security Event Tokens, see RFC 8417,
+// and OpenID Connect Back-Channel Logout 1.0 logout tokens which are profile of them.
+package secevent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amidgo/jwt"
+)
+
+// HeaderType is typ header of Security Event Tokens, see RFC 8417 section 2.3.
+const HeaderType = "secevent+jwt"
+
+var (
+	ErrInvalidType     jwt.TokenInvalidError = "invalid_token_type"
+	ErrInvalidIssuer   jwt.TokenInvalidError = "invalid_issuer"
+	ErrInvalidAudience jwt.TokenInvalidError = "invalid_audience"
+	ErrMissingClaim    jwt.TokenInvalidError = "missing_claim"
+	ErrInvalidEvents   jwt.TokenInvalidError = "invalid_events"
+	ErrNonceNotAllowed jwt.TokenInvalidError = "nonce_not_allowed"
+)
+
+var (
+	ErrNoEvents      = errors.New("at least one event is required")
+	ErrEventNotFound = errors.New("event not found")
+)
+
+// SecurityEventToken is parsed Security Event Token, see RFC 8417 section 2.2.
+type SecurityEventToken struct {
+	Issuer        string
+	Audience      []string
+	IssuedAt      time.Time
+	ID            string
+	Subject       string
+	TransactionID string
+	// TimeOfEvent is zero when toe claim is absent
+	TimeOfEvent time.Time
+	// Events are event payloads by event type uri
+	Events map[string]json.RawMessage
+	Token  jwt.Token
+}
+
+// Event decodes payload of event with eventType into event, ErrEventNotFound is returned when token has no such event.
+func (s SecurityEventToken) Event(eventType string, event any) error {
+	payload, ok := s.Events[eventType]
+	if !ok {
+		return ErrEventNotFound
+	}
+	return json.Unmarshal(payload, event)
+}
+
+// Event is security event to create, Payload is marshaled to JSON object, nil Payload is empty object.
+type Event struct {
+	Type    string
+	Payload any
+}
+
+// SecurityEvent describes token to create.
+type SecurityEvent struct {
+	Subject       string
+	Audience      []string
+	TransactionID string
+	TimeOfEvent   time.Time
+	Events        []Event
+}
+
+type CreatorOption func(c *creatorConfig)
+
+type creatorConfig struct {
+	clock jwt.Clock
+	ttl   time.Duration
+}
+
+// WithClock sets clock used for iat and exp claims.
+func WithClock(clock jwt.Clock) CreatorOption {
+	return func(c *creatorConfig) {
+		c.clock = clock
+	}
+}
+
+// WithTTL sets exp claim, SETs have no exp by default.
+func WithTTL(ttl time.Duration) CreatorOption {
+	return func(c *creatorConfig) {
+		c.ttl = ttl
+	}
+}
+
+// Creator creates Security Event Tokens with secevent+jwt typ, iss, iat and jti claims.
+type Creator struct {
+	creator *jwt.JwtTokenCreator
+	clock   jwt.Clock
+}
+
+func NewCreator(signingMethod jwt.SigningMethod, issuer string, opts ...CreatorOption) *Creator {
+	return newCreator(signingMethod, HeaderType, issuer, opts...)
+}
+
+func newCreator(signingMethod jwt.SigningMethod, headerType, issuer string, opts ...CreatorOption) *Creator {
+	config := creatorConfig{clock: jwt.SystemClock}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	creatorOpts := []jwt.CreatorOption{
+		jwt.WithHeaderType(headerType),
+		jwt.WithIssuer(issuer),
+		jwt.WithTokenID(),
+		jwt.WithClock(config.clock),
+	}
+	if config.ttl > 0 {
+		creatorOpts = append(creatorOpts, jwt.WithTTL(config.ttl))
+	}
+
+	return &Creator{
+		creator: jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, creatorOpts...),
+		clock:   config.clock,
+	}
+}
+
+func (c *Creator) CreateToken(ctx context.Context, securityEvent SecurityEvent) (string, error) {
+	return c.createToken(ctx, securityEvent, nil)
+}
+
+// createToken creates token with profile claims, e.g. sid of logout tokens.
+func (c *Creator) createToken(ctx context.Context, securityEvent SecurityEvent, claims jwt.Payload) (string, error) {
+	if len(securityEvent.Events) == 0 {
+		return "", ErrNoEvents
+	}
+
+	events := make(map[string]any, len(securityEvent.Events))
+	for _, event := range securityEvent.Events {
+		payload := event.Payload
+		if payload == nil {
+			payload = struct{}{}
+		}
+		events[event.Type] = payload
+	}
+
+	payload := make(jwt.Payload, len(claims)+6)
+	for claim, value := range claims {
+		payload[claim] = value
+	}
+	payload["iat"] = c.clock.Now().Unix()
+	payload["events"] = events
+	if securityEvent.Subject != "" {
+		payload["sub"] = securityEvent.Subject
+	}
+	switch len(securityEvent.Audience) {
+	case 0:
+	case 1:
+		payload["aud"] = securityEvent.Audience[0]
+	default:
+		payload["aud"] = securityEvent.Audience
+	}
+	if securityEvent.TransactionID != "" {
+		payload["txn"] = securityEvent.TransactionID
+	}
+	if !securityEvent.TimeOfEvent.IsZero() {
+		payload["toe"] = securityEvent.TimeOfEvent.Unix()
+	}
+
+	return c.creator.CreateTokenContext(ctx, payload)
+}
+
+type ParserOption func(p *Parser)
+
+// WithAudience requires aud claim to contain audience.
+func WithAudience(audience string) ParserOption {
+	return func(p *Parser) {
+		p.audience = audience
+	}
+}
+
+// WithReplayStore enables jti replay protection, tokens without exp claim are rejected
+// because jti is remembered until the token expires.
+func WithReplayStore(store jwt.ReplayStore) ParserOption {
+	return func(p *Parser) {
+		p.replayStore = store
+	}
+}
+
+// WithParserClock sets clock used to check exp claim.
+func WithParserClock(clock jwt.Clock) ParserOption {
+	return func(p *Parser) {
+		p.clock = clock
+	}
+}
+
+// Parser parses Security Event Tokens verified by JwtTokenParser or other parser.
+// Token must have secevent+jwt typ, iss, iat, jti and events claims and must not have nonce claim,
+// so ID tokens and access tokens of the issuer can't be used as SETs, see RFC 8417 section 4.
+type Parser struct {
+	parser     jwt.ContextTokenParser
+	headerType string
+	issuer     string
+	audience   string
+	clock      jwt.Clock
+	// requireExp is set by profiles that require exp claim, e.g. logout tokens
+	requireExp  bool
+	replayStore jwt.ReplayStore
+}
+
+func NewParser(parser jwt.ContextTokenParser, issuer string, opts ...ParserOption) *Parser {
+	return newParser(parser, HeaderType, issuer, opts...)
+}
+
+func newParser(parser jwt.ContextTokenParser, headerType, issuer string, opts ...ParserOption) *Parser {
+	p := &Parser{
+		parser:     parser,
+		headerType: headerType,
+		issuer:     issuer,
+		clock:      jwt.SystemClock,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Parser) Parse(ctx context.Context, token string) (SecurityEventToken, error) {
+	return p.parse(ctx, token)
+}
+
+// parse validates token, profileValidators check profile rules before replay protection.
+func (p *Parser) parse(ctx context.Context, token string, profileValidators ...jwt.TokenValidator) (SecurityEventToken, error) {
+	var securityEventToken SecurityEventToken
+	validators := []jwt.TokenValidator{
+		jwt.TokenValidatorFunc(func(token jwt.Token) (err error) {
+			securityEventToken, err = p.validate(token)
+			return err
+		}),
+	}
+	validators = append(validators, profileValidators...)
+	if p.replayStore != nil {
+		validators = append(validators, jwt.NewBackgroundTokenValidator(jwt.NewReplayValidator(p.replayStore)))
+	}
+
+	_, err := jwt.ParseAndValidateTokenContext(ctx, p.parser, token, validators...)
+	if err != nil {
+		return SecurityEventToken{}, err
+	}
+	return securityEventToken, nil
+}
+
+func (p *Parser) validate(token jwt.Token) (SecurityEventToken, error) {
+	// media type could have application/ prefix, see RFC 7515 section 4.1.9
+	typ := strings.ToLower(token.Header.Type)
+	if typ != p.headerType && typ != "application/"+p.headerType {
+		return SecurityEventToken{}, ErrInvalidType
+	}
+
+	set := SecurityEventToken{Token: token}
+	set.Issuer, _ = token.Payload["iss"].(string)
+	if set.Issuer != p.issuer {
+		return SecurityEventToken{}, ErrInvalidIssuer
+	}
+	set.Audience, _ = jwt.PayloadAudience(token.Payload)
+	if p.audience != "" && !slices.Contains(set.Audience, p.audience) {
+		return SecurityEventToken{}, ErrInvalidAudience
+	}
+
+	if _, ok := token.Payload["nonce"]; ok {
+		return SecurityEventToken{}, ErrNonceNotAllowed
+	}
+
+	var ok bool
+	set.IssuedAt, ok = jwt.PayloadTime(token.Payload, "iat")
+	if !ok {
+		return SecurityEventToken{}, ErrMissingClaim
+	}
+	set.ID, _ = token.Payload["jti"].(string)
+	if set.ID == "" {
+		return SecurityEventToken{}, ErrMissingClaim
+	}
+	exp, ok := jwt.PayloadTime(token.Payload, "exp")
+	if !ok && p.requireExp {
+		return SecurityEventToken{}, jwt.ErrNoExpiration
+	}
+	if ok && !p.clock.Now().Before(exp) {
+		return SecurityEventToken{}, jwt.ErrTokenExpired
+	}
+
+	set.Subject, _ = token.Payload["sub"].(string)
+	set.TransactionID, _ = token.Payload["txn"].(string)
+	set.TimeOfEvent, _ = jwt.PayloadTime(token.Payload, "toe")
+
+	events, err := decodeEvents(token.Payload)
+	if err != nil {
+		return SecurityEventToken{}, err
+	}
+	set.Events = events
+
+	return set, nil
+}
+
+// decodeEvents returns events claim, it must be non-empty JSON object of JSON objects.
+func decodeEvents(payload jwt.Payload) (map[string]json.RawMessage, error) {
+	events, ok := payload["events"].(map[string]any)
+	if !ok || len(events) == 0 {
+		return nil, ErrInvalidEvents
+	}
+
+	result := make(map[string]json.RawMessage, len(events))
+	for eventType, event := range events {
+		if _, ok := event.(map[string]any); !ok {
+			return nil, ErrInvalidEvents
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEvents, err)
+		}
+		result[eventType] = data
+	}
+	return result, nil
+}
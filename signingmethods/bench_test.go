@@ -0,0 +1,120 @@
+package signingmethods_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+const benchmarkSigningString = "eyJ0eXAiOiJKV1QiLCJhbGciOiJIUzI1NiJ9.eyJuYW1lIjoiZGltYSIsInJvbGVzIjpbImFkbWluIiwidXNlciJdLCJ1c2VyX2lkIjoxMDB9"
+
+func benchmarkSigningMethods(b *testing.B) []jwt.SigningMethod {
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ec256Key, err := signingmethods.ParseECPrivateKeyFromPEM(ec256PrivateKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ec384Key, err := signingmethods.ParseECPrivateKeyFromPEM(ec384PrivateKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ec512Key, err := signingmethods.ParseECPrivateKeyFromPEM(ec512PrivateKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return []jwt.SigningMethod{
+		signingmethods.NewHS256("totally secret secret"),
+		signingmethods.NewHS384("totally secret secret"),
+		signingmethods.NewHS512("totally secret secret"),
+		signingmethods.NewRS256(rsaKey),
+		signingmethods.NewRS384(rsaKey),
+		signingmethods.NewRS512(rsaKey),
+		signingmethods.NewES256(ec256Key),
+		signingmethods.NewES384(ec384Key),
+		signingmethods.NewES512(ec512Key),
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	for _, signingMethod := range benchmarkSigningMethods(b) {
+		b.Run(signingMethod.Alg(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := signingMethod.Sign(benchmarkSigningString)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	for _, signingMethod := range benchmarkSigningMethods(b) {
+		sign, err := signingMethod.Sign(benchmarkSigningString)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(signingMethod.Alg(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := signingMethod.Verify(benchmarkSigningString, sign)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHMACUnpooled is HMAC verification with keyed hasher created per call, baseline of BenchmarkVerify/HS*.
+func BenchmarkHMACUnpooled(b *testing.B) {
+	for _, hash := range []crypto.Hash{crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		secret := []byte("totally secret secret")
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(benchmarkSigningString))
+		sign := mac.Sum(nil)
+
+		b.Run(hash.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mac := hmac.New(hash.New, secret)
+				mac.Write([]byte(benchmarkSigningString))
+				if !hmac.Equal(sign, mac.Sum(nil)) {
+					b.Fatal("sign not equal")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyParallel(b *testing.B) {
+	for _, signingMethod := range benchmarkSigningMethods(b)[:3] {
+		sign, err := signingMethod.Sign(benchmarkSigningString)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(signingMethod.Alg(), func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					err := signingMethod.Verify(benchmarkSigningString, sign)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package signingmethods
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"math/big"
+)
+
+// ES signs with ECDSA, signature is R and S concatenated, see RFC 7518 section 3.4.
+type ES struct {
+	private *ecdsa.PrivateKey
+	public  *ecdsa.PublicKey
+	hash    crypto.Hash
+	keySize int
+	hashers *hasherPool
+}
+
+func newES(private *ecdsa.PrivateKey, public *ecdsa.PublicKey, hash crypto.Hash, keySize int) *ES {
+	return &ES{private: private, public: public, hash: hash, keySize: keySize, hashers: newHasherPool(hash.New)}
+}
+
+func (e *ES) Sign(signingString string) ([]byte, error) {
+	if e.private == nil {
+		return nil, ErrMissingPrivateKey
+	}
+
+	hasher := e.hashers.get()
+	defer e.hashers.put(hasher)
+
+	r, s, err := ecdsa.Sign(rand.Reader, e.private, hasher.sum(signingString))
+	if err != nil {
+		return nil, err
+	}
+
+	sign := make([]byte, 2*e.keySize)
+	r.FillBytes(sign[:e.keySize])
+	s.FillBytes(sign[e.keySize:])
+
+	return sign, nil
+}
+
+func (e *ES) Verify(signed string, sign []byte) error {
+	if len(sign) != 2*e.keySize {
+		return ErrSignatureInvalid
+	}
+
+	hasher := e.hashers.get()
+	defer e.hashers.put(hasher)
+
+	r := new(big.Int).SetBytes(sign[:e.keySize])
+	s := new(big.Int).SetBytes(sign[e.keySize:])
+	if !ecdsa.Verify(e.public, hasher.sum(signed), r, s) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
+
+type ES256 struct{ *ES }
+
+func (e *ES256) Alg() string {
+	return "ES256"
+}
+
+func NewES256(private *ecdsa.PrivateKey) *ES256 {
+	return &ES256{ES: newES(private, &private.PublicKey, crypto.SHA256, 32)}
+}
+
+// NewES256Verifier returns ES256 that can only verify signs.
+func NewES256Verifier(public *ecdsa.PublicKey) *ES256 {
+	return &ES256{ES: newES(nil, public, crypto.SHA256, 32)}
+}
+
+type ES384 struct{ *ES }
+
+func (e *ES384) Alg() string {
+	return "ES384"
+}
+
+func NewES384(private *ecdsa.PrivateKey) *ES384 {
+	return &ES384{ES: newES(private, &private.PublicKey, crypto.SHA384, 48)}
+}
+
+// NewES384Verifier returns ES384 that can only verify signs.
+func NewES384Verifier(public *ecdsa.PublicKey) *ES384 {
+	return &ES384{ES: newES(nil, public, crypto.SHA384, 48)}
+}
+
+type ES512 struct{ *ES }
+
+func (e *ES512) Alg() string {
+	return "ES512"
+}
+
+func NewES512(private *ecdsa.PrivateKey) *ES512 {
+	return &ES512{ES: newES(private, &private.PublicKey, crypto.SHA512, 66)}
+}
+
+// NewES512Verifier returns ES512 that can only verify signs.
+func NewES512Verifier(public *ecdsa.PublicKey) *ES512 {
+	return &ES512{ES: newES(nil, public, crypto.SHA512, 66)}
+}
@@ -0,0 +1,42 @@
+package signingmethods_test
+
+import (
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func FuzzVerify(f *testing.F) {
+	f.Add("eyJ0eXAiOiJKV1QiLCJhbGciOiJIUzI1NiJ9.eyJuYW1lIjoiZGltYSIsInJvbGVzIjpbImFkbWluIiwidXNlciJdLCJ1c2VyX2lkIjoxMDB9", []byte("sign"))
+	f.Add("", []byte{})
+	f.Add("header.payload", make([]byte, 64))
+	f.Add("header.payload", make([]byte, 256))
+
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(privateKey)
+	assert.NilError(f, err)
+	ecKey, err := signingmethods.ParseECPrivateKeyFromPEM(ec256PrivateKey)
+	assert.NilError(f, err)
+
+	signingMethods := []jwt.SigningMethod{
+		signingmethods.NewHS256("totally secret secret"),
+		signingmethods.NewHS512("totally secret secret"),
+		signingmethods.NewRS256(rsaKey),
+		signingmethods.NewES256(ecKey),
+	}
+
+	f.Fuzz(func(t *testing.T, signingString string, sign []byte) {
+		for _, signingMethod := range signingMethods {
+			err := signingMethod.Verify(signingString, sign)
+			if err == nil {
+				t.Fatalf("%s verified sign %x of %q", signingMethod.Alg(), sign, signingString)
+			}
+			if !errors.Is(err, signingmethods.ErrSignatureInvalid) && !errors.Is(err, rsa.ErrVerification) {
+				t.Fatalf("%s returned unexpected error %q", signingMethod.Alg(), err)
+			}
+		}
+	})
+}
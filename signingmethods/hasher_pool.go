@@ -0,0 +1,44 @@
+package signingmethods
+
+import (
+	"hash"
+	"sync"
+	"unsafe"
+)
+
+// hasherPool reuses hashers between Sign and Verify calls and is safe for concurrent use.
+// HMAC hasher is keyed once, Reset restores its precomputed inner and outer padded state.
+type hasherPool struct {
+	pool sync.Pool
+}
+
+type pooledHasher struct {
+	hash   hash.Hash
+	digest []byte
+}
+
+func newHasherPool(newHash func() hash.Hash) *hasherPool {
+	p := &hasherPool{}
+	p.pool.New = func() any {
+		h := newHash()
+		return &pooledHasher{hash: h, digest: make([]byte, 0, h.Size())}
+	}
+	return p
+}
+
+func (p *hasherPool) get() *pooledHasher {
+	return p.pool.Get().(*pooledHasher)
+}
+
+func (p *hasherPool) put(h *pooledHasher) {
+	p.pool.Put(h)
+}
+
+// sum returns digest of the signing string, the digest is valid until the hasher is put back to the pool.
+func (h *pooledHasher) sum(signingString string) []byte {
+	h.hash.Reset()
+	// hash.Hash never modifies or retains written bytes, so the string is written without copy
+	h.hash.Write(unsafe.Slice(unsafe.StringData(signingString), len(signingString)))
+	h.digest = h.hash.Sum(h.digest[:0])
+	return h.digest
+}
@@ -0,0 +1,84 @@
+package signingmethods_test
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+// TestSigningMethodsConcurrent shares every signing method between goroutines, run it with -race.
+func TestSigningMethodsConcurrent(t *testing.T) {
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(privateKey)
+	assert.NilError(t, err)
+	ecKey, err := signingmethods.ParseECPrivateKeyFromPEM(ec256PrivateKey)
+	assert.NilError(t, err)
+
+	signingMethods := []jwt.SigningMethod{
+		signingmethods.NewHS256("totally secret secret"),
+		signingmethods.NewHS512("totally secret secret"),
+		signingmethods.NewRS256(rsaKey),
+		signingmethods.NewES256(ecKey),
+	}
+
+	for _, signingMethod := range signingMethods {
+		signingMethod := signingMethod
+		t.Run(signingMethod.Alg(), func(t *testing.T) {
+			var wg sync.WaitGroup
+			errs := make(chan error, 16)
+			for g := 0; g < 16; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 20; i++ {
+						signingString := fmt.Sprintf("header.payload-%d-%d", g, i)
+						sign, err := signingMethod.Sign(signingString)
+						if err != nil {
+							errs <- err
+							return
+						}
+						err = signingMethod.Verify(signingString, sign)
+						if err != nil {
+							errs <- fmt.Errorf("verify %s: %w", signingString, err)
+							return
+						}
+						if signingMethod.Verify(signingString+"x", sign) == nil {
+							errs <- fmt.Errorf("verified changed %s", signingString)
+							return
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestHMACPooledSign checks pooled hasher against hmac.New, including sign returned before the hasher is reused.
+func TestHMACPooledSign(t *testing.T) {
+	hs256 := signingmethods.NewHS256("totally secret secret")
+
+	signs := make([][]byte, 0, 3)
+	for _, signingString := range []string{"a.b", "", "header.payload"} {
+		sign, err := hs256.Sign(signingString)
+		assert.NilError(t, err)
+		signs = append(signs, sign)
+
+		mac := hmac.New(crypto.SHA256.New, []byte("totally secret secret"))
+		mac.Write([]byte(signingString))
+		assert.DeepEqual(t, sign, mac.Sum(nil))
+	}
+
+	assert.Assert(t, !hmac.Equal(signs[0], signs[1]))
+	assert.Assert(t, !hmac.Equal(signs[1], signs[2]))
+}
@@ -1,40 +1,44 @@
 package signingmethods
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/hmac"
 	_ "crypto/sha256"
 	_ "crypto/sha512"
 	"errors"
+	stdhash "hash"
 )
 
 var ErrSignatureInvalid = errors.New("signature invalid")
 
+// HS signs with HMAC, keyed hashers are pooled so the padded keys are derived once per hasher.
 type HS struct {
-	secret []byte
-	hash   crypto.Hash
+	secret  []byte
+	hash    crypto.Hash
+	hashers *hasherPool
 }
 
-func (h *HS) Sign(signingString string) ([]byte, error) {
-	hasher := hmac.New(h.hash.New, h.secret)
+func newHS(secret string, hash crypto.Hash) *HS {
+	h := &HS{secret: []byte(secret), hash: hash}
+	h.hashers = newHasherPool(func() stdhash.Hash {
+		return hmac.New(h.hash.New, h.secret)
+	})
+	return h
+}
 
-	_, err := hasher.Write([]byte(signingString))
-	if err != nil {
-		return nil, err
-	}
+func (h *HS) Sign(signingString string) ([]byte, error) {
+	hasher := h.hashers.get()
+	defer h.hashers.put(hasher)
 
-	return hasher.Sum(nil), nil
+	return bytes.Clone(hasher.sum(signingString)), nil
 }
 
 func (h *HS) Verify(signed string, sign []byte) error {
-	hasher := hmac.New(h.hash.New, h.secret)
-
-	_, err := hasher.Write([]byte(signed))
-	if err != nil {
-		return err
-	}
+	hasher := h.hashers.get()
+	defer h.hashers.put(hasher)
 
-	if !hmac.Equal(sign, hasher.Sum(nil)) {
+	if !hmac.Equal(sign, hasher.sum(signed)) {
 		return ErrSignatureInvalid
 	}
 
@@ -44,7 +48,7 @@ func (h *HS) Verify(signed string, sign []byte) error {
 type HS256 struct{ *HS }
 
 func NewHS256(secret string) *HS256 {
-	return &HS256{HS: &HS{secret: []byte(secret), hash: crypto.SHA256}}
+	return &HS256{HS: newHS(secret, crypto.SHA256)}
 }
 
 func (h *HS256) Alg() string {
@@ -54,7 +58,7 @@ func (h *HS256) Alg() string {
 type HS384 struct{ *HS }
 
 func NewHS384(secret string) *HS384 {
-	return &HS384{HS: &HS{secret: []byte(secret), hash: crypto.SHA384}}
+	return &HS384{HS: newHS(secret, crypto.SHA384)}
 }
 
 func (h *HS384) Alg() string {
@@ -64,7 +68,7 @@ func (h *HS384) Alg() string {
 type HS512 struct{ *HS }
 
 func NewHS512(secret string) *HS512 {
-	return &HS512{HS: &HS{secret: []byte(secret), hash: crypto.SHA512}}
+	return &HS512{HS: newHS(secret, crypto.SHA512)}
 }
 
 func (h *HS512) Alg() string {
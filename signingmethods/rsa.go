@@ -10,29 +10,31 @@ import (
 
 type RS struct {
 	private *rsa.PrivateKey
+	public  *rsa.PublicKey
 	hash    crypto.Hash
+	hashers *hasherPool
 }
 
-func (r *RS) Sign(singingString string) ([]byte, error) {
-	hasher := r.hash.New()
+func newRS(private *rsa.PrivateKey, public *rsa.PublicKey, hash crypto.Hash) *RS {
+	return &RS{private: private, public: public, hash: hash, hashers: newHasherPool(hash.New)}
+}
 
-	_, err := hasher.Write([]byte(singingString))
-	if err != nil {
-		return nil, err
+func (r *RS) Sign(singingString string) ([]byte, error) {
+	if r.private == nil {
+		return nil, ErrMissingPrivateKey
 	}
 
-	return rsa.SignPKCS1v15(rand.Reader, r.private, r.hash, hasher.Sum(nil))
+	hasher := r.hashers.get()
+	defer r.hashers.put(hasher)
+
+	return rsa.SignPKCS1v15(rand.Reader, r.private, r.hash, hasher.sum(singingString))
 }
 
 func (r *RS) Verify(signed string, sign []byte) error {
-	hasher := r.hash.New()
-
-	_, err := hasher.Write([]byte(signed))
-	if err != nil {
-		return err
-	}
+	hasher := r.hashers.get()
+	defer r.hashers.put(hasher)
 
-	return rsa.VerifyPKCS1v15(&r.private.PublicKey, r.hash, hasher.Sum(nil), sign)
+	return rsa.VerifyPKCS1v15(r.public, r.hash, hasher.sum(signed), sign)
 }
 
 type RS256 struct{ *RS }
@@ -42,7 +44,12 @@ func (r *RS256) Alg() string {
 }
 
 func NewRS256(private *rsa.PrivateKey) *RS256 {
-	return &RS256{RS: &RS{private: private, hash: crypto.SHA256}}
+	return &RS256{RS: newRS(private, &private.PublicKey, crypto.SHA256)}
+}
+
+// NewRS256Verifier returns RS256 that can only verify signs.
+func NewRS256Verifier(public *rsa.PublicKey) *RS256 {
+	return &RS256{RS: newRS(nil, public, crypto.SHA256)}
 }
 
 type RS384 struct{ *RS }
@@ -52,7 +59,12 @@ func (r RS384) Alg() string {
 }
 
 func NewRS384(private *rsa.PrivateKey) *RS384 {
-	return &RS384{RS: &RS{private: private, hash: crypto.SHA384}}
+	return &RS384{RS: newRS(private, &private.PublicKey, crypto.SHA384)}
+}
+
+// NewRS384Verifier returns RS384 that can only verify signs.
+func NewRS384Verifier(public *rsa.PublicKey) *RS384 {
+	return &RS384{RS: newRS(nil, public, crypto.SHA384)}
 }
 
 type RS512 struct{ *RS }
@@ -62,5 +74,10 @@ func (r RS512) Alg() string {
 }
 
 func NewRS512(private *rsa.PrivateKey) *RS512 {
-	return &RS512{RS: &RS{private: private, hash: crypto.SHA512}}
+	return &RS512{RS: newRS(private, &private.PublicKey, crypto.SHA512)}
+}
+
+// NewRS512Verifier returns RS512 that can only verify signs.
+func NewRS512Verifier(public *rsa.PublicKey) *RS512 {
+	return &RS512{RS: newRS(nil, public, crypto.SHA512)}
 }
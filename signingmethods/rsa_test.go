@@ -1,6 +1,7 @@
 package signingmethods_test
 
 import (
+	"encoding/base64"
 	"testing"
 
 	_ "embed"
@@ -109,3 +110,26 @@ func TestRSAParse(t *testing.T) {
 		},
 	)
 }
+
+var (
+	//go:embed testdata/sample_key.pub
+	publicKey []byte
+)
+
+func TestRSAVerifier(t *testing.T) {
+	private, err := signingmethods.ParseRSAPrivateKeyFromPEM(privateKey)
+	assert.NilError(t, err)
+	public, err := signingmethods.ParseRSAPublicKeyFromPEM(publicKey)
+	assert.NilError(t, err)
+
+	accessToken, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingmethods.NewRS256(private)).CreateToken(jwt.Payload{"name": "dima"})
+	assert.NilError(t, err)
+
+	verifier := signingmethods.NewRS256Verifier(public)
+
+	_, err = jwt.NewTokenParser(base64.RawURLEncoding, verifier).ParseToken(accessToken)
+	assert.NilError(t, err)
+
+	_, err = jwt.NewTokenCreator(base64.RawURLEncoding, verifier).CreateToken(jwt.Payload{"name": "dima"})
+	assert.ErrorIs(t, err, signingmethods.ErrMissingPrivateKey)
+}
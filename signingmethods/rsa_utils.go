@@ -11,6 +11,7 @@ var (
 	ErrKeyMustBePEMEncoded = errors.New("invalid key: Key must be a PEM encoded PKCS1 or PKCS8 key")
 	ErrNotRSAPrivateKey    = errors.New("key is not a valid RSA private key")
 	ErrNotRSAPublicKey     = errors.New("key is not a valid RSA public key")
+	ErrMissingPrivateKey   = errors.New("private key required for signing")
 )
 
 // ParseRSAPrivateKeyFromPEM parses a PEM encoded PKCS1 or PKCS8 private key
@@ -0,0 +1,61 @@
+package signingmethods
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+
+	"github.com/amidgo/jwt"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("unsupported alg")
+	ErrKeyAlgMismatch = errors.New("key does not match alg")
+)
+
+// NewVerifier returns signing method of alg that can only verify signs with public key, e.g. key from JWK Set.
+// RS and ES algorithms are supported, public must be *rsa.PublicKey or *ecdsa.PublicKey on the alg curve.
+func NewVerifier(alg string, public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch alg {
+	case "RS256", "RS384", "RS512":
+		rsaPublic, ok := public.(*rsa.PublicKey)
+		if !ok {
+			return nil, ErrKeyAlgMismatch
+		}
+		switch alg {
+		case "RS256":
+			return NewRS256Verifier(rsaPublic), nil
+		case "RS384":
+			return NewRS384Verifier(rsaPublic), nil
+		default:
+			return NewRS512Verifier(rsaPublic), nil
+		}
+	case "ES256", "ES384", "ES512":
+		ecPublic, ok := public.(*ecdsa.PublicKey)
+		if !ok || ecPublic.Curve.Params().BitSize != curveBits(alg) {
+			return nil, ErrKeyAlgMismatch
+		}
+		switch alg {
+		case "ES256":
+			return NewES256Verifier(ecPublic), nil
+		case "ES384":
+			return NewES384Verifier(ecPublic), nil
+		default:
+			return NewES512Verifier(ecPublic), nil
+		}
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+func curveBits(alg string) int {
+	switch alg {
+	case "ES256":
+		return 256
+	case "ES384":
+		return 384
+	default:
+		return 521
+	}
+}
@@ -0,0 +1,39 @@
+package signingmethods_test
+
+import (
+	"testing"
+
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	rsaPublic, err := signingmethods.ParseRSAPublicKeyFromPEM(publicKey)
+	assert.NilError(t, err)
+	ecPublic, err := signingmethods.ParseECPublicKeyFromPEM(ec256PublicKey)
+	assert.NilError(t, err)
+
+	cases := []struct {
+		alg         string
+		public      any
+		expectedErr error
+	}{
+		{alg: "RS256", public: rsaPublic},
+		{alg: "RS384", public: rsaPublic},
+		{alg: "RS512", public: rsaPublic},
+		{alg: "ES256", public: ecPublic},
+		{alg: "ES384", public: ecPublic, expectedErr: signingmethods.ErrKeyAlgMismatch},
+		{alg: "RS256", public: ecPublic, expectedErr: signingmethods.ErrKeyAlgMismatch},
+		{alg: "ES256", public: rsaPublic, expectedErr: signingmethods.ErrKeyAlgMismatch},
+		{alg: "HS256", public: rsaPublic, expectedErr: signingmethods.ErrUnsupportedAlg},
+		{alg: "none", public: rsaPublic, expectedErr: signingmethods.ErrUnsupportedAlg},
+	}
+
+	for _, cs := range cases {
+		verifier, err := signingmethods.NewVerifier(cs.alg, cs.public)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.alg)
+		if cs.expectedErr == nil {
+			assert.Equal(t, verifier.Alg(), cs.alg)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+)
+
+var benchmarkPayload = jwt.Payload{
+	"sub":     "1234567890",
+	"name":    "dima",
+	"roles":   []string{"admin", "user"},
+	"user_id": 100,
+	"exp":     4102444800,
+}
+
+func BenchmarkCreateToken(b *testing.B) {
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, signingmethods.NewHS256("totally secret secret"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := creator.CreateToken(benchmarkPayload)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRawToken(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := jwt.ParseRawToken(parseClaimsTestToken)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseToken(b *testing.B) {
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewHS256("totally secret secret"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := parser.ParseToken(parseClaimsTestToken)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseClaims(b *testing.B) {
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingmethods.NewHS256("totally secret secret"))
+
+	b.Run("struct", func(b *testing.B) {
+		var claims parseClaimsTestClaims
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := parser.ParseClaims(parseClaimsTestToken, &claims)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("payload", func(b *testing.B) {
+		payload := jwt.Payload{}
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			clear(payload)
+			_, err := parser.ParseClaims(parseClaimsTestToken, &payload)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package jwt_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"gotest.tools/v3/assert"
+)
+
+func Test_PayloadTime(t *testing.T) {
+	expected := time.Unix(1700000000, 0)
+	cases := []struct {
+		name     string
+		value    any
+		expected time.Time
+		ok       bool
+	}{
+		{name: "float64", value: float64(1700000000), expected: expected, ok: true},
+		{name: "int64", value: int64(1700000000), expected: expected, ok: true},
+		{name: "int", value: 1700000000, expected: expected, ok: true},
+		{name: "json number", value: json.Number("1700000000"), expected: expected, ok: true},
+		{name: "fractional json number", value: json.Number("1700000000.5")},
+		{name: "string", value: "1700000000"},
+		{name: "absent"},
+	}
+
+	for _, cs := range cases {
+		payload := jwt.Payload{}
+		if cs.value != nil {
+			payload["exp"] = cs.value
+		}
+
+		actual, ok := jwt.PayloadTime(payload, "exp")
+		assert.Equal(t, ok, cs.ok, cs.name)
+		assert.Assert(t, actual.Equal(cs.expected), cs.name)
+	}
+}
+
+func Test_PayloadAudience(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    any
+		expected []string
+		ok       bool
+	}{
+		{name: "string", value: "api", expected: []string{"api"}, ok: true},
+		{name: "strings", value: []string{"api", "web"}, expected: []string{"api", "web"}, ok: true},
+		{name: "decoded array", value: []any{"api", "web"}, expected: []string{"api", "web"}, ok: true},
+		{name: "array with number", value: []any{"api", float64(1)}},
+		{name: "number", value: float64(1)},
+		{name: "absent"},
+	}
+
+	for _, cs := range cases {
+		payload := jwt.Payload{}
+		if cs.value != nil {
+			payload["aud"] = cs.value
+		}
+
+		actual, ok := jwt.PayloadAudience(payload)
+		assert.Equal(t, ok, cs.ok, cs.name)
+		assert.DeepEqual(t, actual, cs.expected)
+	}
+}
+
+func Test_NewTokenID(t *testing.T) {
+	first, err := jwt.NewTokenID()
+	assert.NilError(t, err)
+	second, err := jwt.NewTokenID()
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(first), 22)
+	assert.Assert(t, first != second)
+}
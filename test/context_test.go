@@ -0,0 +1,207 @@
+package jwt_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	jwtmocks "github.com/amidgo/jwt/mocks"
+	"github.com/amidgo/jwt/signingmethods"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+type contextKey struct{}
+
+func Test_ContextSigningMethod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+	hs256 := signingmethods.NewHS256("secret")
+	isRequestContext := gomock.AssignableToTypeOf(ctx)
+
+	remoteSigner := jwtmocks.NewMockContextSigningMethod(ctrl)
+	remoteSigner.EXPECT().Alg().Return("HS256").AnyTimes()
+	remoteSigner.EXPECT().SignContext(isRequestContext, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, stringToSign string) ([]byte, error) {
+			assert.Equal(t, ctx.Value(contextKey{}), "request")
+			return hs256.Sign(stringToSign)
+		})
+	remoteSigner.EXPECT().VerifyContext(isRequestContext, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, verifyData string, sign []byte) error {
+			assert.Equal(t, ctx.Value(contextKey{}), "request")
+			return hs256.Verify(verifyData, sign)
+		}).Times(2)
+
+	signingMethod := jwt.NewBackgroundSigningMethod(remoteSigner)
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod)
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+
+	accessToken, err := creator.CreateTokenContext(ctx, jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	token, err := parser.ParseTokenContext(ctx, accessToken)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token.Payload, jwt.Payload{"sub": "user"})
+
+	var claims jwt.Payload
+	_, err = parser.ParseClaimsContext(ctx, accessToken, &claims)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, claims, jwt.Payload{"sub": "user"})
+}
+
+func Test_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	hs256 := signingmethods.NewHS256("secret")
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, hs256)
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, hs256)
+
+	accessToken, err := creator.CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	_, err = creator.CreateTokenContext(ctx, jwt.Payload{"sub": "user"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = parser.ParseTokenContext(ctx, accessToken)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var claims jwt.Payload
+	_, err = parser.ParseClaimsContext(ctx, accessToken, &claims)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = jwt.NewContextSigningMethod(hs256).SignContext(ctx, "data")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_ContextSigningMethod_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	hs256 := signingmethods.NewHS256("secret")
+	transportErr := fmt.Errorf("kms request failed, %w", jwt.ErrSigningMethodUnavailable)
+
+	accessToken, err := jwt.NewTokenCreator(base64.RawURLEncoding, hs256).CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name        string
+		verifyErr   error
+		expectedErr error
+		notVerified bool
+	}{
+		{name: "unavailable", verifyErr: transportErr, expectedErr: jwt.ErrSigningMethodUnavailable},
+		{name: "deadline", verifyErr: context.DeadlineExceeded, expectedErr: context.DeadlineExceeded},
+		{name: "wrong signature", verifyErr: signingmethods.ErrSignatureInvalid, expectedErr: jwt.ErrSignNotVerified, notVerified: true},
+	}
+
+	for _, cs := range cases {
+		remoteSigner := jwtmocks.NewMockContextSigningMethod(ctrl)
+		remoteSigner.EXPECT().Alg().Return("HS256").AnyTimes()
+		remoteSigner.EXPECT().VerifyContext(gomock.Any(), gomock.Any(), gomock.Any()).Return(cs.verifyErr).Times(2)
+		parser := jwt.NewTokenParser(base64.RawURLEncoding, jwt.NewBackgroundSigningMethod(remoteSigner))
+
+		_, err := parser.ParseTokenContext(context.Background(), accessToken)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		assert.Equal(t, errors.Is(err, jwt.ErrSignNotVerified), cs.notVerified, cs.name)
+
+		var parseErr *jwt.ParseError
+		assert.Assert(t, errors.As(err, &parseErr), cs.name)
+		assert.Equal(t, parseErr.Stage, jwt.StageSignature, cs.name)
+
+		var claims jwt.Payload
+		_, err = parser.ParseClaimsContext(context.Background(), accessToken, &claims)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		assert.Equal(t, errors.Is(err, jwt.ErrSignNotVerified), cs.notVerified, cs.name)
+	}
+}
+
+func Test_ContextAdapters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	token := jwt.Token{Payload: jwt.Payload{"sub": "user"}}
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("token").Return(token, nil).Times(1)
+
+	contextParser := jwt.NewContextTokenParser(parser)
+	_, err := contextParser.ParseTokenContext(cancelled, "token")
+	assert.ErrorIs(t, err, context.Canceled)
+	parsed, err := contextParser.ParseTokenContext(context.Background(), "token")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed, token)
+
+	creator := jwtmocks.NewMockTokenCreator(ctrl)
+	creator.EXPECT().CreateToken(token.Payload).Return("token", nil).Times(1)
+
+	contextCreator := jwt.NewContextTokenCreator(creator)
+	_, err = contextCreator.CreateTokenContext(cancelled, token.Payload)
+	assert.ErrorIs(t, err, context.Canceled)
+	created, err := contextCreator.CreateTokenContext(context.Background(), token.Payload)
+	assert.NilError(t, err)
+	assert.Equal(t, created, "token")
+
+	backgroundParser := jwtmocks.NewMockContextTokenParser(ctrl)
+	backgroundParser.EXPECT().ParseTokenContext(context.Background(), "token").Return(token, nil)
+	parsed, err = jwt.NewBackgroundTokenParser(backgroundParser).ParseToken("token")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed, token)
+
+	backgroundCreator := jwtmocks.NewMockContextTokenCreator(ctrl)
+	backgroundCreator.EXPECT().CreateTokenContext(context.Background(), token.Payload).Return("token", nil)
+	created, err = jwt.NewBackgroundTokenCreator(backgroundCreator).CreateToken(token.Payload)
+	assert.NilError(t, err)
+	assert.Equal(t, created, "token")
+
+	hs256 := signingmethods.NewHS256("secret")
+	assert.Equal(t, jwt.NewBackgroundSigningMethod(jwt.NewContextSigningMethod(hs256)), jwt.SigningMethod(hs256))
+}
+
+func Test_ValidateTokenContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	token := jwt.Token{Payload: jwt.Payload{"sub": "user"}}
+	errInvalid := errors.New("invalid")
+
+	contextValidator := jwtmocks.NewMockContextTokenValidator(ctrl)
+	contextValidator.EXPECT().ValidateTokenContext(ctx, token).Return(errInvalid)
+
+	validator := jwtmocks.NewMockTokenValidator(ctrl)
+	validator.EXPECT().ValidateToken(token).Return(nil)
+
+	err := jwt.ValidateTokenContext(ctx, token, validator, jwt.NewBackgroundTokenValidator(contextValidator))
+	assert.ErrorIs(t, err, errInvalid)
+
+	err = jwt.ValidateTokenContext(cancelled, token, validator)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = jwt.ValidateTokenContext(cancelled, token, jwt.NewRevocationValidator(jwt.NewMemoryRevocationStore()))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = jwt.ValidateTokenContext(cancelled, jwt.Token{Payload: jwt.Payload{"jti": "id", "exp": float64(time.Now().Add(time.Hour).Unix())}},
+		jwt.NewReplayValidator(jwt.NewMemoryReplayStore()),
+	)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_CachingTokenParser_Context(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx := context.WithValue(context.Background(), contextKey{}, "request")
+	token := jwt.Token{Payload: jwt.Payload{"exp": float64(time.Now().Add(time.Hour).Unix())}}
+
+	parser := jwtmocks.NewMockContextTokenParser(ctrl)
+	parser.EXPECT().ParseTokenContext(ctx, "token").Return(token, nil).Times(1)
+
+	cache := jwt.NewCachingTokenParser(jwt.NewBackgroundTokenParser(parser), 10, 1<<20)
+
+	for range [2]struct{}{} {
+		parsed, err := cache.ParseTokenContext(ctx, "token")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, parsed, token)
+	}
+}
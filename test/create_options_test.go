@@ -0,0 +1,147 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func Test_CreateToken_RegisteredClaims(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	clock := jwt.ClockFunc(func() time.Time { return now })
+	signingMethod := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+
+	cases := []struct {
+		name            string
+		opts            []jwt.CreatorOption
+		payload         jwt.Payload
+		expectedPayload jwt.Payload
+	}{
+		{
+			name:            "no options",
+			payload:         jwt.Payload{"sub": "user"},
+			expectedPayload: jwt.Payload{"sub": "user"},
+		},
+		{
+			name: "all options",
+			opts: []jwt.CreatorOption{
+				jwt.WithClock(clock),
+				jwt.WithTTL(time.Hour),
+				jwt.WithNotBefore(-time.Minute),
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("api"),
+			},
+			payload: jwt.Payload{"sub": "user"},
+			expectedPayload: jwt.Payload{
+				"sub": "user",
+				"iat": float64(now.Unix()),
+				"exp": float64(now.Add(time.Hour).Unix()),
+				"nbf": float64(now.Add(-time.Minute).Unix()),
+				"iss": "issuer",
+				"aud": "api",
+			},
+		},
+		{
+			name: "claims present in payload",
+			opts: []jwt.CreatorOption{
+				jwt.WithClock(clock),
+				jwt.WithTTL(time.Hour),
+				jwt.WithIssuer("issuer"),
+				jwt.WithAudience("api", "admin"),
+			},
+			payload: jwt.Payload{"exp": 100, "iss": "other", "jti": "id"},
+			expectedPayload: jwt.Payload{
+				"jti": "id",
+				"iat": float64(now.Unix()),
+				"exp": float64(100),
+				"iss": "other",
+				"aud": []any{"api", "admin"},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		creator := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, cs.opts...)
+
+		accessToken, err := creator.CreateToken(cs.payload)
+		assert.NilError(t, err, cs.name)
+
+		token, err := parser.ParseToken(accessToken)
+		assert.NilError(t, err, cs.name)
+
+		// random jti is set whenever registered claims options are used
+		jti, ok := token.Payload["jti"].(string)
+		assert.Equal(t, ok && jti != "", len(cs.opts) > 0, cs.name)
+		if _, ok := cs.expectedPayload["jti"]; !ok {
+			delete(token.Payload, "jti")
+		}
+		assert.DeepEqual(t, token.Payload, cs.expectedPayload)
+	}
+}
+
+func Test_CreateToken_TokenID(t *testing.T) {
+	signingMethod := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, jwt.WithTokenID())
+
+	payload := jwt.Payload{"sub": "user"}
+	ids := make(map[any]struct{})
+	for i := 0; i < 10; i++ {
+		accessToken, err := creator.CreateToken(payload)
+		assert.NilError(t, err)
+
+		token, err := parser.ParseToken(accessToken)
+		assert.NilError(t, err)
+		assert.Assert(t, token.Payload["jti"] != "")
+		ids[token.Payload["jti"]] = struct{}{}
+	}
+	assert.Equal(t, len(ids), 10)
+	assert.DeepEqual(t, payload, jwt.Payload{"sub": "user"})
+
+	accessToken, err := creator.CreateToken(jwt.Payload{"jti": "fixed"})
+	assert.NilError(t, err)
+	token, err := parser.ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Payload["jti"], "fixed")
+}
+
+func Test_CreateToken_HeaderType(t *testing.T) {
+	signingMethod := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+
+	accessToken, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, jwt.WithHeaderType("at+jwt")).CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	rawToken, err := jwt.ParseRawToken(accessToken)
+	assert.NilError(t, err)
+	header, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	assert.NilError(t, err)
+	assert.Equal(t, string(header), `{"typ":"at+jwt","alg":"HS256"}`)
+
+	token, err := parser.ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Header, jwt.Header{Type: "at+jwt", Alg: "HS256"})
+}
+
+func Test_CreateToken_KeyID(t *testing.T) {
+	signingMethod := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, signingMethod)
+
+	accessToken, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingMethod, jwt.WithKeyID("key-1")).CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	rawToken, err := jwt.ParseRawToken(accessToken)
+	assert.NilError(t, err)
+	header, err := base64.RawURLEncoding.DecodeString(rawToken.Header())
+	assert.NilError(t, err)
+	assert.Equal(t, string(header), `{"typ":"JWT","alg":"HS256","kid":"key-1"}`)
+
+	token, err := parser.ParseToken(accessToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.Header, jwt.Header{Type: "JWT", Alg: "HS256", KeyID: "key-1"})
+}
@@ -0,0 +1,104 @@
+package jwt_test
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+// rfc7797HMACKey is HMAC key of RFC 7515 appendix A.1 used by RFC 7797 section 4 examples.
+func rfc7797HMACKey(t *testing.T) string {
+	key, err := base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	assert.NilError(t, err)
+	return string(key)
+}
+
+func Test_Detached_RFC7797(t *testing.T) {
+	const (
+		encodedToken   = "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ"
+		unencodedToken = "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+	)
+	content := []byte("$.02")
+	signingMethod := signingmethods.NewHS256(rfc7797HMACKey(t))
+
+	creator := jwt.NewContentCreator(base64.RawURLEncoding, signingMethod)
+	parser := jwt.NewContentParser(base64.RawURLEncoding, signingMethod)
+
+	token, err := creator.CreateDetached(content)
+	assert.NilError(t, err)
+	assert.Equal(t, token, encodedToken)
+
+	token, err = creator.CreateUnencodedDetached(content)
+	assert.NilError(t, err)
+	assert.Equal(t, token, unencodedToken)
+
+	cases := []struct {
+		name        string
+		token       string
+		content     []byte
+		expectedErr error
+	}{
+		{name: "encoded", token: encodedToken, content: content},
+		{name: "unencoded", token: unencodedToken, content: content},
+		{
+			name:        "encoded, wrong content",
+			token:       encodedToken,
+			content:     []byte("$.03"),
+			expectedErr: signingmethods.ErrSignatureInvalid,
+		},
+		{
+			name:        "unencoded, wrong content",
+			token:       unencodedToken,
+			content:     []byte("$.03"),
+			expectedErr: signingmethods.ErrSignatureInvalid,
+		},
+		{
+			name:        "attached payload",
+			token:       "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ",
+			content:     content,
+			expectedErr: jwt.ErrBadToken,
+		},
+		{
+			name: "b64 not critical",
+			// {"alg":"HS256","b64":false}
+			token:       "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2V9..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY",
+			content:     content,
+			expectedErr: jwt.ErrUnsupportedCrit,
+		},
+		{
+			name: "unknown crit",
+			// {"alg":"HS256","crit":["exp"]}
+			token:       "eyJhbGciOiJIUzI1NiIsImNyaXQiOlsiZXhwIl19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY",
+			content:     content,
+			expectedErr: jwt.ErrUnsupportedCrit,
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := parser.VerifyDetached(cs.token, cs.content)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+	}
+
+	_, err = jwt.NewContentParser(base64.RawURLEncoding, signingmethods.NewHS512("secret")).VerifyDetached(encodedToken, content)
+	assert.ErrorIs(t, err, jwt.ErrWrongAlgoritm)
+}
+
+func Test_Content_Attached(t *testing.T) {
+	rs256, _ := loadJSONSigningMethods(t)
+	content := []byte{0x00, 0xff, '.', 'x'}
+
+	token, err := jwt.NewContentCreator(base64.RawURLEncoding, rs256).CreateToken(content)
+	assert.NilError(t, err)
+
+	header, actual, err := jwt.NewContentParser(base64.RawURLEncoding, rs256).ParseContent(token)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, header, jwt.Header{Alg: "RS256"})
+	assert.DeepEqual(t, actual, content)
+
+	_, _, err = jwt.NewContentParser(base64.RawURLEncoding, rs256).ParseContent(token[:len(token)-4])
+	assert.ErrorIs(t, err, rsa.ErrVerification)
+}
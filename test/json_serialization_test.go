@@ -0,0 +1,209 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func loadJSONSigningMethods(t *testing.T) (*signingmethods.RS256, *signingmethods.ES256) {
+	rsaPEM, err := os.ReadFile("../signingmethods/testdata/sample_key")
+	assert.NilError(t, err)
+	rsaKey, err := signingmethods.ParseRSAPrivateKeyFromPEM(rsaPEM)
+	assert.NilError(t, err)
+
+	ecPEM, err := os.ReadFile("../signingmethods/testdata/ec256-private.pem")
+	assert.NilError(t, err)
+	ecKey, err := signingmethods.ParseECPrivateKeyFromPEM(ecPEM)
+	assert.NilError(t, err)
+
+	return signingmethods.NewRS256(rsaKey), signingmethods.NewES256(ecKey)
+}
+
+func Test_JSONSerialization_General(t *testing.T) {
+	rs256, es256 := loadJSONSigningMethods(t)
+	payload := jwt.Payload{"document": "contract.pdf"}
+
+	creator := jwt.NewJSONTokenCreator(base64.RawURLEncoding,
+		jwt.JSONSigner{SigningMethod: rs256, Header: map[string]any{"kid": "rsa"}},
+		jwt.JSONSigner{SigningMethod: es256, Header: map[string]any{"kid": "ec"}},
+	)
+	jws, err := creator.CreateGeneral(payload)
+	assert.NilError(t, err)
+	assert.Equal(t, len(jws.Signatures), 2)
+	assert.Equal(t, jws.Signatures[1].Header["kid"], "ec")
+
+	data, err := json.Marshal(jws)
+	assert.NilError(t, err)
+
+	tamperedJWS := jws
+	tamperedJWS.Signatures = []jwt.JSONSignature{jws.Signatures[0], jws.Signatures[1]}
+	tamperedJWS.Signatures[1].Signature = jws.Signatures[0].Signature
+	tampered, err := json.Marshal(tamperedJWS)
+	assert.NilError(t, err)
+
+	cases := []struct {
+		name            string
+		parser          *jwt.JSONTokenParser
+		data            []byte
+		expectedHeaders []jwt.Header
+		expectedErr     error
+	}{
+		{
+			name:            "all verified",
+			parser:          jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAll, rs256, es256),
+			data:            data,
+			expectedHeaders: []jwt.Header{{Type: "JWT", Alg: "RS256", KeyID: "rsa"}, {Type: "JWT", Alg: "ES256", KeyID: "ec"}},
+		},
+		{
+			name:            "any verified",
+			parser:          jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAny, es256),
+			data:            data,
+			expectedHeaders: []jwt.Header{{Type: "JWT", Alg: "ES256", KeyID: "ec"}},
+		},
+		{
+			name:        "all required, unknown alg",
+			parser:      jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAll, es256),
+			data:        data,
+			expectedErr: jwt.ErrWrongAlgoritm,
+		},
+		{
+			name:        "all required, wrong sign",
+			parser:      jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAll, rs256, es256),
+			data:        tampered,
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+		{
+			name:            "any required, wrong sign",
+			parser:          jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAny, rs256, es256),
+			data:            tampered,
+			expectedHeaders: []jwt.Header{{Type: "JWT", Alg: "RS256", KeyID: "rsa"}},
+		},
+		{
+			name:        "any required, nothing verified",
+			parser:      jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAny, signingmethods.NewHS256("secret")),
+			data:        data,
+			expectedErr: jwt.ErrSignNotVerified,
+		},
+		{
+			name:        "not json",
+			parser:      jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAny, rs256),
+			data:        []byte("a.b.c"),
+			expectedErr: jwt.ErrBadToken,
+		},
+	}
+
+	for _, cs := range cases {
+		token, err := cs.parser.ParseJSON(cs.data)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr != nil {
+			continue
+		}
+		assert.DeepEqual(t, token.Headers, cs.expectedHeaders)
+		assert.DeepEqual(t, token.Payload, payload)
+	}
+}
+
+func Test_JSONSerialization_Flattened(t *testing.T) {
+	rs256, es256 := loadJSONSigningMethods(t)
+	payload := jwt.Payload{"document": "contract.pdf"}
+
+	jws, err := jwt.NewJSONTokenCreator(base64.RawURLEncoding, jwt.JSONSigner{SigningMethod: es256}).CreateFlattened(payload)
+	assert.NilError(t, err)
+
+	data, err := json.Marshal(jws)
+	assert.NilError(t, err)
+
+	token, err := jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAll, es256).ParseJSON(data)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token, jwt.JSONToken{Headers: []jwt.Header{{Type: "JWT", Alg: "ES256"}}, Payload: payload})
+
+	compact := jws.Protected + "." + jws.Payload + "." + jws.Signature
+	parsed, err := jwt.NewTokenParser(base64.RawURLEncoding, es256).ParseToken(compact)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parsed.Payload, payload)
+
+	_, err = jwt.NewJSONTokenCreator(base64.RawURLEncoding,
+		jwt.JSONSigner{SigningMethod: rs256},
+		jwt.JSONSigner{SigningMethod: es256},
+	).CreateFlattened(payload)
+	assert.ErrorIs(t, err, jwt.ErrSingleSignatureRequired)
+}
+
+func Test_JSONSerialization_UnprotectedHeader(t *testing.T) {
+	hs256 := signingmethods.NewHS256("secret")
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user"}`))
+	encode := func(header string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(header))
+	}
+	sign := func(protected string) string {
+		sign, err := hs256.Sign(protected + "." + payload)
+		assert.NilError(t, err)
+		return base64.RawURLEncoding.EncodeToString(sign)
+	}
+	flattened := func(protected string, header map[string]any) []byte {
+		data, err := json.Marshal(jwt.FlattenedJWS{
+			Payload:       payload,
+			JSONSignature: jwt.JSONSignature{Protected: protected, Header: header, Signature: sign(protected)},
+		})
+		assert.NilError(t, err)
+		return data
+	}
+
+	cases := []struct {
+		name           string
+		data           []byte
+		expectedHeader jwt.Header
+		expectedErr    error
+	}{
+		{
+			name:           "alg in unprotected header",
+			data:           flattened("", map[string]any{"alg": "HS256", "kid": "hs"}),
+			expectedHeader: jwt.Header{Alg: "HS256", KeyID: "hs"},
+		},
+		{
+			name:           "headers are merged",
+			data:           flattened(encode(`{"alg":"HS256"}`), map[string]any{"kid": "hs"}),
+			expectedHeader: jwt.Header{Alg: "HS256", KeyID: "hs"},
+		},
+		{
+			name:        "duplicate header parameter",
+			data:        flattened(encode(`{"alg":"HS256"}`), map[string]any{"alg": "HS256"}),
+			expectedErr: jwt.ErrDuplicateHeader,
+		},
+		{
+			name:        "crit in unprotected header",
+			data:        flattened(encode(`{"alg":"HS256"}`), map[string]any{"crit": []string{"exp"}}),
+			expectedErr: jwt.ErrBadToken,
+		},
+		{
+			name:        "crit in protected header",
+			data:        flattened(encode(`{"alg":"HS256","crit":["exp"],"exp":1}`), nil),
+			expectedErr: jwt.ErrUnsupportedCrit,
+		},
+		{
+			name:        "no alg",
+			data:        flattened("", map[string]any{"kid": "hs"}),
+			expectedErr: jwt.ErrBadToken,
+		},
+	}
+
+	for _, cs := range cases {
+		headers, _, err := jwt.NewJSONTokenParser(base64.RawURLEncoding, jwt.VerifyAll, hs256).ParseJSONContent(cs.data)
+		assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+		if cs.expectedErr != nil {
+			continue
+		}
+		assert.DeepEqual(t, headers, []jwt.Header{cs.expectedHeader})
+	}
+
+	_, err := jwt.NewJSONTokenCreator(base64.RawURLEncoding,
+		jwt.JSONSigner{SigningMethod: hs256, Header: map[string]any{"alg": "none"}},
+	).CreateGeneral(jwt.Payload{"sub": "user"})
+	assert.ErrorIs(t, err, jwt.ErrDuplicateHeader)
+}
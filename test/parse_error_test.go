@@ -0,0 +1,135 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ParseError(t *testing.T) {
+	hs256 := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, hs256)
+
+	accessToken, err := jwt.NewTokenCreator(base64.RawURLEncoding, hs256).CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+	segments := strings.Split(accessToken, ".")
+
+	hs384Token, err := jwt.NewTokenCreator(base64.RawURLEncoding, signingmethods.NewHS384("secret")).CreateToken(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	notJSON := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+
+	cases := []struct {
+		name        string
+		token       string
+		expectedErr error
+		expected    jwt.ParseError
+		// ParseClaims verifies sign before payload is decoded
+		signatureFirst bool
+	}{
+		{
+			name:        "two segments",
+			token:       segments[0] + "." + segments[1],
+			expectedErr: jwt.ErrBadToken,
+			expected:    jwt.ParseError{Stage: jwt.StageSplit, Segment: jwt.NoSegment},
+		},
+		{
+			name:        "header not base64",
+			token:       "!." + segments[1] + "." + segments[2],
+			expectedErr: jwt.ErrBadToken,
+			expected:    jwt.ParseError{Stage: jwt.StageDecodeHeader, Segment: jwt.HeaderSegment},
+		},
+		{
+			name:        "header not json",
+			token:       notJSON + "." + segments[1] + "." + segments[2],
+			expectedErr: jwt.ErrUnmarshalToken,
+			expected:    jwt.ParseError{Stage: jwt.StageDecodeHeader, Segment: jwt.HeaderSegment},
+		},
+		{
+			name:        "payload not json",
+			token:       segments[0] + "." + notJSON + "." + segments[2],
+			expectedErr: jwt.ErrUnmarshalToken,
+			expected:    jwt.ParseError{Stage: jwt.StageDecodePayload, Segment: jwt.PayloadSegment, Alg: "HS256"},
+
+			signatureFirst: true,
+		},
+		{
+			name:        "wrong alg",
+			token:       hs384Token,
+			expectedErr: jwt.ErrWrongAlgoritm,
+			expected:    jwt.ParseError{Stage: jwt.StageAlgCheck, Segment: jwt.HeaderSegment, Alg: "HS384"},
+		},
+		{
+			name:        "sign not base64",
+			token:       segments[0] + "." + segments[1] + ".!",
+			expectedErr: jwt.ErrBadToken,
+			expected:    jwt.ParseError{Stage: jwt.StageSignature, Segment: jwt.SignSegment, Alg: "HS256"},
+		},
+		{
+			name:        "wrong sign",
+			token:       segments[0] + "." + segments[1] + "." + segments[2][1:],
+			expectedErr: jwt.ErrSignNotVerified,
+			expected:    jwt.ParseError{Stage: jwt.StageSignature, Segment: jwt.SignSegment, Alg: "HS256"},
+		},
+	}
+
+	for _, cs := range cases {
+		_, err := parser.ParseToken(cs.token)
+		errs := []error{err}
+		if !cs.signatureFirst {
+			var claims jwt.Payload
+			_, err = parser.ParseClaims(cs.token, &claims)
+			errs = append(errs, err)
+		}
+
+		for _, err := range errs {
+			assert.ErrorIs(t, err, cs.expectedErr, cs.name)
+
+			var target *jwt.ParseError
+			assert.Assert(t, errors.As(err, &target), cs.name)
+			assert.Equal(t, target.Stage, cs.expected.Stage, cs.name)
+			assert.Equal(t, target.Segment, cs.expected.Segment, cs.name)
+			assert.Equal(t, target.Alg, cs.expected.Alg, cs.name)
+		}
+	}
+}
+
+func Test_ParseError_Error(t *testing.T) {
+	err := &jwt.ParseError{Stage: jwt.StageSignature, Segment: jwt.SignSegment, Alg: "HS256", Err: jwt.ErrSignNotVerified}
+	assert.Equal(t, err.Error(), "failed signature stage, segment 2, alg HS256, wrong_sign")
+
+	err = &jwt.ParseError{Stage: jwt.StageSplit, Segment: jwt.NoSegment, Err: jwt.ErrBadToken}
+	assert.Equal(t, err.Error(), "failed split stage, bad_token")
+}
+
+func Test_ParseAndValidateToken(t *testing.T) {
+	hs256 := signingmethods.NewHS256("secret")
+	parser := jwt.NewTokenParser(base64.RawURLEncoding, hs256)
+	creator := jwt.NewTokenCreator(base64.RawURLEncoding, hs256)
+
+	validToken, err := creator.CreateToken(jwt.Payload{"exp": time.Now().Add(time.Hour).Unix()})
+	assert.NilError(t, err)
+	expiredToken, err := creator.CreateToken(jwt.Payload{"exp": time.Now().Add(-time.Hour).Unix()})
+	assert.NilError(t, err)
+
+	_, err = jwt.ParseAndValidateToken(parser, validToken, jwt.VerifyTokenExpiration)
+	assert.NilError(t, err)
+
+	_, err = jwt.ParseAndValidateToken(parser, expiredToken, jwt.VerifyTokenExpiration)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	var target *jwt.ParseError
+	assert.Assert(t, errors.As(err, &target))
+	assert.Equal(t, target.Stage, jwt.StageValidation)
+	assert.Equal(t, target.Segment, jwt.NoSegment)
+	assert.Equal(t, target.Alg, "HS256")
+
+	_, err = jwt.ParseAndValidateToken(parser, "bad", jwt.VerifyTokenExpiration)
+	assert.Assert(t, errors.As(err, &target))
+	assert.Equal(t, target.Stage, jwt.StageSplit)
+}
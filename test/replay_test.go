@@ -0,0 +1,124 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	jwtmocks "github.com/amidgo/jwt/mocks"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ReplayValidator(t *testing.T) {
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	validator := jwt.NewReplayValidator(jwt.NewMemoryReplayStore())
+
+	cases := []struct {
+		token       jwt.Token
+		expectedErr error
+	}{
+		{
+			token: jwt.Token{Payload: jwt.Payload{"jti": "reset-1", "exp": exp}},
+		},
+		{
+			token:       jwt.Token{Payload: jwt.Payload{"jti": "reset-1", "exp": exp}},
+			expectedErr: jwt.ErrTokenReplayed,
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{"jti": "reset-2", "exp": exp}},
+		},
+		{
+			token:       jwt.Token{Payload: jwt.Payload{"exp": exp}},
+			expectedErr: jwt.ErrNoTokenID,
+		},
+		{
+			token:       jwt.Token{Payload: jwt.Payload{"jti": "reset-3"}},
+			expectedErr: jwt.ErrNoExpiration,
+		},
+	}
+
+	for _, cs := range cases {
+		actualErr := jwt.ValidateToken(cs.token, jwt.VerifyTokenExpiration, validator)
+		assert.ErrorIs(t, actualErr, cs.expectedErr, "wrong err")
+	}
+}
+
+func Test_ReplayValidator_Concurrent(t *testing.T) {
+	const goroutines = 64
+
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	validator := jwt.NewReplayValidator(jwt.NewMemoryReplayStore())
+
+	for i := 0; i < 10; i++ {
+		token := jwt.Token{Payload: jwt.Payload{"jti": fmt.Sprintf("token-%d", i), "exp": exp}}
+
+		var accepted atomic.Int32
+		var wg sync.WaitGroup
+		wg.Add(goroutines)
+		for g := 0; g < goroutines; g++ {
+			go func() {
+				defer wg.Done()
+				if validator.ValidateToken(token) == nil {
+					accepted.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, accepted.Load(), int32(1), "token must be accepted exactly once")
+	}
+}
+
+func Test_ReplayValidator_StoreFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storeErr := errors.New("store unavailable")
+	exp := time.Now().Add(time.Hour).Unix()
+
+	store := jwtmocks.NewMockReplayStore(ctrl)
+	store.EXPECT().Record("id", time.Unix(exp, 0)).Return(false, storeErr).Times(1)
+
+	err := jwt.NewReplayValidator(store).ValidateToken(jwt.Token{Payload: jwt.Payload{"jti": "id", "exp": float64(exp)}})
+	assert.ErrorIs(t, err, storeErr)
+}
+
+func Test_RecordTokenID_ContextDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	store := jwtmocks.NewMockReplayStore(ctrl)
+	store.EXPECT().Record(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := jwt.RecordTokenID(ctx, store, "id", time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_ReplayValidator_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	exp := float64(time.Now().Add(-time.Minute).Unix())
+
+	store := jwtmocks.NewMockReplayStore(ctrl)
+	store.EXPECT().Record(gomock.Any(), gomock.Any()).Times(0)
+
+	validator := jwt.NewReplayValidator(store)
+	for i := 0; i < 2; i++ {
+		err := validator.ValidateToken(jwt.Token{Payload: jwt.Payload{"jti": "expired", "exp": exp}})
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	}
+}
+
+func Test_MemoryReplayStore_Expired(t *testing.T) {
+	store := jwt.NewMemoryReplayStore()
+
+	for i := 0; i < 2; i++ {
+		firstUse, err := store.Record("expired", time.Now().Add(-time.Minute))
+		assert.NilError(t, err)
+		assert.Assert(t, !firstUse, "expired id must not be reported as first use")
+	}
+}
@@ -0,0 +1,87 @@
+package jwt_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	jwtmocks "github.com/amidgo/jwt/mocks"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func Test_RevocationValidator(t *testing.T) {
+	now := time.Now()
+	store := jwt.NewMemoryRevocationStore()
+
+	revokedToken := jwt.Token{Payload: jwt.Payload{
+		"jti": "revoked",
+		"sub": "user",
+		"iat": float64(now.Unix()),
+		"exp": float64(now.Add(time.Hour).Unix()),
+	}}
+	assert.NilError(t, jwt.RevokeToken(store, revokedToken))
+	assert.NilError(t, store.RevokeSubject("locked", now, now.Add(time.Hour)))
+	assert.NilError(t, store.RevokeID("expired", now.Add(-time.Second)))
+
+	validator := jwt.NewRevocationValidator(store)
+
+	cases := []struct {
+		token       jwt.Token
+		expectedErr error
+	}{
+		{
+			token:       revokedToken,
+			expectedErr: jwt.ErrTokenRevoked,
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{"jti": "active", "sub": "user"}},
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{"jti": "expired"}},
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{
+				"jti": "old",
+				"sub": "locked",
+				"iat": float64(now.Add(-time.Minute).Unix()),
+			}},
+			expectedErr: jwt.ErrTokenRevoked,
+		},
+		{
+			token:       jwt.Token{Payload: jwt.Payload{"sub": "locked"}},
+			expectedErr: jwt.ErrTokenRevoked,
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{
+				"sub": "locked",
+				"iat": float64(now.Add(time.Minute).Unix()),
+			}},
+		},
+		{
+			token: jwt.Token{Payload: jwt.Payload{}},
+		},
+	}
+
+	for _, cs := range cases {
+		actualErr := jwt.ValidateToken(cs.token, validator)
+		assert.ErrorIs(t, actualErr, cs.expectedErr, "wrong err")
+	}
+}
+
+func Test_RevokeToken_NoTokenID(t *testing.T) {
+	err := jwt.RevokeToken(jwt.NewMemoryRevocationStore(), jwt.Token{Payload: jwt.Payload{}})
+	assert.ErrorIs(t, err, jwt.ErrNoTokenID)
+}
+
+func Test_RevocationValidator_StoreFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storeErr := errors.New("store unavailable")
+
+	store := jwtmocks.NewMockRevocationStore(ctrl)
+	store.EXPECT().IsIDRevoked("id").Return(false, storeErr).Times(1)
+
+	err := jwt.NewRevocationValidator(store).ValidateToken(jwt.Token{Payload: jwt.Payload{"jti": "id"}})
+	assert.ErrorIs(t, err, storeErr)
+}
@@ -0,0 +1,238 @@
+package jwt_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	jwtmocks "github.com/amidgo/jwt/mocks"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func cachedToken(exp time.Time) jwt.Token {
+	return jwt.Token{
+		Header:  jwt.Header{Type: "JWT", Alg: "HS256"},
+		Payload: jwt.Payload{"sub": "user", "exp": float64(exp.Unix())},
+	}
+}
+
+func Test_CachingTokenParser_Hit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("token").Return(cachedToken(now.Add(time.Hour)), nil).Times(1)
+
+	cache := jwt.NewCachingTokenParser(parser, 10, 1<<20, jwt.WithCacheClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	token, err := cache.ParseToken("token")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token, cachedToken(now.Add(time.Hour)))
+
+	token.Payload["sub"] = "modified"
+
+	token, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token, cachedToken(now.Add(time.Hour)))
+
+	assert.Equal(t, cache.Stats().Hits, uint64(1))
+	assert.Equal(t, cache.Stats().Misses, uint64(1))
+	assert.Equal(t, cache.Stats().Entries, 1)
+}
+
+func Test_CachingTokenParser_NestedClaims(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+	nestedToken := func() jwt.Token {
+		token := cachedToken(now.Add(time.Hour))
+		token.Payload["roles"] = []any{"user", map[string]any{"scope": "read"}}
+		token.Payload["address"] = map[string]any{"country": "RU", "lines": []any{"first"}}
+		return token
+	}
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("token").Return(nestedToken(), nil).Times(1)
+
+	cache := jwt.NewCachingTokenParser(parser, 10, 1<<20, jwt.WithCacheClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	for range [2]struct{}{} {
+		token, err := cache.ParseToken("token")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, token, nestedToken())
+
+		token.Payload["roles"].([]any)[0] = "admin"
+		token.Payload["roles"].([]any)[1].(map[string]any)["scope"] = "write"
+		token.Payload["address"].(map[string]any)["country"] = "US"
+		token.Payload["address"].(map[string]any)["lines"].([]any)[0] = "modified"
+	}
+
+	token, err := cache.ParseToken("token")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, token, nestedToken())
+	assert.Equal(t, cache.Stats().Hits, uint64(2))
+}
+
+func Test_CachingTokenParser_NotCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("invalid").Return(jwt.Token{}, jwt.ErrSignNotVerified).Times(2)
+	parser.EXPECT().ParseToken("no exp").Return(jwt.Token{Payload: jwt.Payload{"sub": "user"}}, nil).Times(2)
+	parser.EXPECT().ParseToken("expired").Return(cachedToken(now.Add(-time.Second)), nil).Times(2)
+
+	cache := jwt.NewCachingTokenParser(parser, 10, 1<<20, jwt.WithCacheClock(jwt.ClockFunc(func() time.Time { return now })))
+
+	for range [2]struct{}{} {
+		_, err := cache.ParseToken("invalid")
+		assert.ErrorIs(t, err, jwt.ErrSignNotVerified)
+
+		_, err = cache.ParseToken("no exp")
+		assert.NilError(t, err)
+
+		_, err = cache.ParseToken("expired")
+		assert.NilError(t, err)
+	}
+
+	stats := cache.Stats()
+	assert.Equal(t, stats.Hits, uint64(0))
+	assert.Equal(t, stats.Misses, uint64(6))
+	assert.Equal(t, stats.Entries, 0)
+}
+
+func Test_CachingTokenParser_Expiration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+	clock := now
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("token").Return(cachedToken(now.Add(time.Minute)), nil).Times(2)
+
+	cache := jwt.NewCachingTokenParser(parser, 10, 1<<20, jwt.WithCacheClock(jwt.ClockFunc(func() time.Time { return clock })))
+
+	_, err := cache.ParseToken("token")
+	assert.NilError(t, err)
+
+	clock = now.Add(time.Minute)
+
+	_, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+
+	assert.Equal(t, cache.Stats().Hits, uint64(0))
+	assert.Equal(t, cache.Stats().Misses, uint64(2))
+}
+
+func Test_CachingTokenParser_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+	token := cachedToken(now.Add(time.Hour))
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken(gomock.Any()).Return(token, nil).AnyTimes()
+
+	cache := jwt.NewCachingTokenParser(parser, 2, 1<<20)
+	for _, accessToken := range []string{"first", "second", "first", "third"} {
+		_, err := cache.ParseToken(accessToken)
+		assert.NilError(t, err)
+	}
+
+	// second is least recently used and was evicted
+	assert.Equal(t, cache.Stats().Entries, 2)
+	assert.Equal(t, cache.Stats().Hits, uint64(1))
+
+	_, err := cache.ParseToken("first")
+	assert.NilError(t, err)
+	_, err = cache.ParseToken("second")
+	assert.NilError(t, err)
+	assert.Equal(t, cache.Stats().Hits, uint64(2))
+
+	cache = jwt.NewCachingTokenParser(parser, 10, 1024)
+	for _, accessToken := range []string{"first", "second", "third", "fourth", "fifth"} {
+		_, err := cache.ParseToken(accessToken)
+		assert.NilError(t, err)
+	}
+	assert.Assert(t, cache.Stats().Bytes <= 1024)
+	assert.Assert(t, cache.Stats().Entries < 5)
+
+	_, err = cache.ParseToken(strings.Repeat("a", 2048))
+	assert.NilError(t, err)
+	assert.Assert(t, cache.Stats().Bytes <= 1024)
+}
+
+func Test_CachingTokenParser_Invalidate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+
+	var cache *jwt.CachingTokenParser
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	gomock.InOrder(
+		parser.EXPECT().ParseToken("token").Return(cachedToken(now.Add(time.Hour)), nil),
+		parser.EXPECT().ParseToken("token").Return(jwt.Token{}, jwt.ErrSignNotVerified),
+		// keys rotated while the token was parsed
+		parser.EXPECT().ParseToken("token").DoAndReturn(func(string) (jwt.Token, error) {
+			cache.Invalidate()
+			return cachedToken(now.Add(time.Hour)), nil
+		}),
+		parser.EXPECT().ParseToken("token").Return(cachedToken(now.Add(time.Hour)), nil),
+	)
+
+	cache = jwt.NewCachingTokenParser(parser, 10, 1<<20)
+
+	_, err := cache.ParseToken("token")
+	assert.NilError(t, err)
+	_, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+
+	cache.Invalidate()
+	assert.Equal(t, cache.Stats().Entries, 0)
+
+	_, err = cache.ParseToken("token")
+	assert.ErrorIs(t, err, jwt.ErrSignNotVerified)
+
+	_, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+	assert.Equal(t, cache.Stats().Entries, 0)
+
+	_, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+	_, err = cache.ParseToken("token")
+	assert.NilError(t, err)
+
+	stats := cache.Stats()
+	assert.Equal(t, stats.Entries, 1)
+	assert.Equal(t, stats.Hits, uint64(2))
+	assert.Equal(t, stats.Misses, uint64(4))
+}
+
+func Test_CachingTokenParser_KeyGeneration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	now := time.Now().Truncate(time.Second)
+	var generation uint64
+
+	parser := jwtmocks.NewMockTokenParser(ctrl)
+	parser.EXPECT().ParseToken("token").Return(cachedToken(now.Add(time.Hour)), nil).Times(2)
+
+	cache := jwt.NewCachingTokenParser(parser, 10, 1<<20, jwt.WithCacheKeyGeneration(func() uint64 { return generation }))
+
+	for range [2]struct{}{} {
+		_, err := cache.ParseToken("token")
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, cache.Stats().Hits, uint64(1))
+
+	// keys rotated, cached token is parsed again
+	generation++
+
+	for range [2]struct{}{} {
+		_, err := cache.ParseToken("token")
+		assert.NilError(t, err)
+	}
+
+	stats := cache.Stats()
+	assert.Equal(t, stats.Entries, 1)
+	assert.Equal(t, stats.Hits, uint64(2))
+	assert.Equal(t, stats.Misses, uint64(2))
+}
@@ -0,0 +1,86 @@
+package jwt_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/amidgo/jwt"
+	"github.com/amidgo/jwt/signingmethods"
+	"gotest.tools/v3/assert"
+)
+
+func newTestTokenPairIssuer() (*jwt.TokenPairIssuer, jwt.TokenCreateParser) {
+	createParser := jwt.NewTokenCreateParser(base64.RawURLEncoding, signingmethods.NewHS256("refresh secret"))
+	issuer := jwt.NewTokenPairIssuer(createParser, jwt.NewMemoryRefreshTokenStore(), time.Minute, time.Hour)
+	return issuer, createParser
+}
+
+func Test_TokenPairIssuer_Issue(t *testing.T) {
+	issuer, parser := newTestTokenPairIssuer()
+
+	pair, err := issuer.IssueTokenPair(jwt.Payload{"sub": "user", "exp": 1, "typ": "custom"})
+	assert.NilError(t, err)
+
+	access, err := parser.ParseToken(pair.AccessToken)
+	assert.NilError(t, err)
+	refresh, err := parser.ParseToken(pair.RefreshToken)
+	assert.NilError(t, err)
+
+	assert.NilError(t, jwt.ValidateToken(access, jwt.VerifyTokenExpiration, jwt.TokenTypeValidator(jwt.AccessTokenType)))
+	assert.NilError(t, jwt.ValidateToken(refresh, jwt.VerifyTokenExpiration, jwt.TokenTypeValidator(jwt.RefreshTokenType)))
+	assert.ErrorIs(t, jwt.TokenTypeValidator(jwt.RefreshTokenType)(access), jwt.ErrWrongTokenType)
+
+	assert.Equal(t, access.Payload["sub"], "user")
+	assert.Equal(t, refresh.Payload["sub"], "user")
+	assert.Equal(t, access.Payload["typ"], "custom")
+	assert.Equal(t, access.Header.Type, "JWT")
+	assert.Equal(t, access.Payload["fid"], refresh.Payload["fid"])
+	assert.Assert(t, access.Payload["jti"] != refresh.Payload["jti"])
+	assert.Assert(t, access.Payload["exp"].(float64) < refresh.Payload["exp"].(float64))
+}
+
+func Test_TokenPairIssuer_Rotate(t *testing.T) {
+	issuer, parser := newTestTokenPairIssuer()
+
+	first, err := issuer.IssueTokenPair(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	second, err := issuer.RefreshTokenPair(first.RefreshToken)
+	assert.NilError(t, err)
+	assert.Assert(t, second.RefreshToken != first.RefreshToken)
+	assert.Assert(t, second.AccessToken != first.AccessToken)
+
+	refresh, err := parser.ParseToken(second.RefreshToken)
+	assert.NilError(t, err)
+	assert.Equal(t, refresh.Payload["sub"], "user")
+
+	third, err := issuer.RefreshTokenPair(second.RefreshToken)
+	assert.NilError(t, err)
+
+	_, err = issuer.RefreshTokenPair(first.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrRefreshTokenReused)
+
+	_, err = issuer.RefreshTokenPair(third.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrTokenFamilyRevoked)
+}
+
+func Test_TokenPairIssuer_RefreshRejected(t *testing.T) {
+	issuer, parser := newTestTokenPairIssuer()
+
+	pair, err := issuer.IssueTokenPair(jwt.Payload{"sub": "user"})
+	assert.NilError(t, err)
+
+	_, err = issuer.RefreshTokenPair(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+
+	_, err = issuer.RefreshTokenPair(pair.RefreshToken[:len(pair.RefreshToken)-4])
+	assert.ErrorIs(t, err, signingmethods.ErrSignatureInvalid)
+
+	refresh, err := parser.ParseToken(pair.RefreshToken)
+	assert.NilError(t, err)
+	assert.NilError(t, issuer.RevokeFamily(refresh.Payload["fid"].(string)))
+
+	_, err = issuer.RefreshTokenPair(pair.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrTokenFamilyRevoked)
+}
@@ -103,3 +103,36 @@ func Test_VerifyTokenExpiration(t *testing.T) {
 		assert.ErrorIs(t, actualErr, cs.expectedErr, "wrong err")
 	}
 }
+
+func Test_VerifyTokenNotBefore(t *testing.T) {
+	cases := []struct {
+		token       jwt.Token
+		expectedErr error
+	}{
+		{
+			token: jwt.Token{
+				Payload: jwt.Payload{
+					"nbf": float64(time.Now().Add(-time.Minute).Unix()),
+				},
+			},
+		},
+		{
+			token: jwt.Token{
+				Payload: jwt.Payload{},
+			},
+		},
+		{
+			token: jwt.Token{
+				Payload: jwt.Payload{
+					"nbf": float64(time.Now().Add(time.Hour).Unix()),
+				},
+			},
+			expectedErr: jwt.ErrTokenNotYetValid,
+		},
+	}
+
+	for _, cs := range cases {
+		actualErr := jwt.VerifyTokenNotBefore(cs.token)
+		assert.ErrorIs(t, actualErr, cs.expectedErr, "wrong err")
+	}
+}
@@ -6,8 +6,9 @@ type Token struct {
 }
 
 type Header struct {
-	Type string `json:"typ"`
-	Alg  string `json:"alg"`
+	Type  string `json:"typ"`
+	Alg   string `json:"alg"`
+	KeyID string `json:"kid,omitempty"`
 }
 
 type Payload map[string]any
@@ -0,0 +1,226 @@
+package jwt
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+)
+
+// cacheEntryOverhead is approximate memory of cache entry besides the token, decoded token takes about the token length.
+const cacheEntryOverhead = 256
+
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+	Bytes   int
+}
+
+type tokenCacheEntry struct {
+	key       [sha256.Size]byte
+	token     Token
+	expiresAt int64
+	size      int
+}
+
+type CacheOption func(p *CachingTokenParser)
+
+// WithCacheKeyGeneration sets func returning generation of the parser keys, e.g. oidc RemoteKeySet Generation.
+// Cache is invalidated when the generation changes, so entries verified with rotated keys are not returned.
+func WithCacheKeyGeneration(keyGeneration func() uint64) CacheOption {
+	return func(p *CachingTokenParser) {
+		p.keyGeneration = keyGeneration
+	}
+}
+
+// WithCacheClock sets clock used to expire cache entries.
+func WithCacheClock(clock Clock) CacheOption {
+	return func(p *CachingTokenParser) {
+		p.clock = clock
+	}
+}
+
+// CachingTokenParser is TokenParser that caches successfully parsed tokens, entries are keyed by SHA-256 of the token.
+// Failed parses are never cached and tokens without exp claim are not cached, entry is kept until the token exp.
+// Least recently used entries are evicted when entry count or approximate memory exceeds the limits.
+// Invalidate must be called when keys of the parser change, e.g. on key rotation, unless WithCacheKeyGeneration is set.
+type CachingTokenParser struct {
+	parser     TokenParser
+	maxEntries int
+	maxBytes   int
+	clock      Clock
+
+	keyGeneration func() uint64
+
+	mu         sync.Mutex
+	entries    map[[sha256.Size]byte]*list.Element
+	lru        *list.List
+	bytes      int
+	generation uint64
+	seenKeys   uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewCachingTokenParser(parser TokenParser, maxEntries, maxBytes int, opts ...CacheOption) *CachingTokenParser {
+	p := &CachingTokenParser{
+		parser:     parser,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		clock:      SystemClock,
+		entries:    make(map[[sha256.Size]byte]*list.Element),
+		lru:        list.New(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.keyGeneration != nil {
+		p.seenKeys = p.keyGeneration()
+	}
+	return p
+}
+
+// ParseToken returns cached token or parses it with the wrapped parser.
+// Payload of the returned token is a copy, so callers may modify it.
+func (p *CachingTokenParser) ParseToken(accessToken string) (Token, error) {
+	return p.ParseTokenContext(context.Background(), accessToken)
+}
+
+// ParseTokenContext is ParseToken that passes ctx to wrapped parser on cache miss, see NewContextTokenParser.
+func (p *CachingTokenParser) ParseTokenContext(ctx context.Context, accessToken string) (Token, error) {
+	key := sha256.Sum256([]byte(accessToken))
+	now := p.clock.Now().Unix()
+
+	var keys uint64
+	if p.keyGeneration != nil {
+		keys = p.keyGeneration()
+	}
+
+	p.mu.Lock()
+	if p.keyGeneration != nil && keys != p.seenKeys {
+		p.seenKeys = keys
+		p.invalidate()
+	}
+	if elem, ok := p.entries[key]; ok {
+		entry := elem.Value.(*tokenCacheEntry)
+		if now < entry.expiresAt {
+			p.lru.MoveToFront(elem)
+			token := copyToken(entry.token)
+			p.mu.Unlock()
+			p.hits.Add(1)
+			return token, nil
+		}
+		p.remove(elem)
+	}
+	generation := p.generation
+	p.mu.Unlock()
+
+	p.misses.Add(1)
+	token, err := NewContextTokenParser(p.parser).ParseTokenContext(ctx, accessToken)
+	if err != nil {
+		return token, err
+	}
+
+	exp, ok := PayloadTime(token.Payload, "exp")
+	if !ok || now >= exp.Unix() {
+		return token, nil
+	}
+
+	entry := &tokenCacheEntry{
+		key:       key,
+		token:     copyToken(token),
+		expiresAt: exp.Unix(),
+		size:      len(accessToken) + cacheEntryOverhead,
+	}
+	p.add(entry, generation)
+
+	return token, nil
+}
+
+// Invalidate removes all entries, tokens parsed before the call are not cached after it.
+func (p *CachingTokenParser) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.invalidate()
+}
+
+func (p *CachingTokenParser) invalidate() {
+	p.generation++
+	p.entries = make(map[[sha256.Size]byte]*list.Element)
+	p.lru.Init()
+	p.bytes = 0
+}
+
+func (p *CachingTokenParser) Stats() CacheStats {
+	p.mu.Lock()
+	entries, bytes := p.lru.Len(), p.bytes
+	p.mu.Unlock()
+
+	return CacheStats{
+		Hits:    p.hits.Load(),
+		Misses:  p.misses.Load(),
+		Entries: entries,
+		Bytes:   bytes,
+	}
+}
+
+func (p *CachingTokenParser) add(entry *tokenCacheEntry, generation uint64) {
+	if entry.size > p.maxBytes || p.maxEntries <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// keys were changed while the token was parsed
+	if generation != p.generation {
+		return
+	}
+	if elem, ok := p.entries[entry.key]; ok {
+		p.remove(elem)
+	}
+	for p.lru.Len() >= p.maxEntries || p.bytes+entry.size > p.maxBytes {
+		p.remove(p.lru.Back())
+	}
+
+	p.entries[entry.key] = p.lru.PushFront(entry)
+	p.bytes += entry.size
+}
+
+func (p *CachingTokenParser) remove(elem *list.Element) {
+	entry := p.lru.Remove(elem).(*tokenCacheEntry)
+	delete(p.entries, entry.key)
+	p.bytes -= entry.size
+}
+
+func copyToken(token Token) Token {
+	payload := make(Payload, len(token.Payload))
+	for claim, value := range token.Payload {
+		payload[claim] = copyValue(value)
+	}
+	return Token{Header: token.Header, Payload: payload}
+}
+
+// copyValue deep copies objects and arrays of decoded JSON, so cached payload is not shared with callers.
+func copyValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(value))
+		for name, field := range value {
+			object[name] = copyValue(field)
+		}
+		return object
+	case []any:
+		array := make([]any, len(value))
+		for i, item := range value {
+			array[i] = copyValue(item)
+		}
+		return array
+	default:
+		return value
+	}
+}
@@ -1,6 +1,10 @@
 package jwt
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 type TokenInvalidError string
 
@@ -27,3 +31,13 @@ func (t TokenInvalidError) Is(target error) bool {
 
 	return false
 }
+
+// wrapTokenInvalid wraps err with code unless err is already TokenInvalidError,
+// so every parse error could be handled by TokenInvalidError code.
+func wrapTokenInvalid(code TokenInvalidError, err error) error {
+	var tokenInvalidErr TokenInvalidError
+	if errors.As(err, &tokenInvalidErr) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", code, err)
+}
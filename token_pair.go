@@ -0,0 +1,233 @@
+package jwt
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source token_pair.go -destination mocks/token_pair_mocks.go -package jwtmocks
+
+var (
+	ErrWrongTokenType     TokenInvalidError = "wrong_token_type"
+	ErrRefreshTokenReused TokenInvalidError = "refresh_token_reused"
+	ErrTokenFamilyRevoked TokenInvalidError = "family_revoked"
+	ErrNoTokenFamily      TokenInvalidError = "no_token_family"
+)
+
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+)
+
+// TokenTypeClaim is claim of AccessTokenType or RefreshTokenType set by TokenPairIssuer.
+// It is not registered claim, so typ claim of the payload and typ header are left to the user.
+const TokenTypeClaim = "token_use"
+
+// TokenTypeValidator checks TokenTypeClaim of the token payload.
+func TokenTypeValidator(typ string) TokenValidatorFunc {
+	return func(t Token) error {
+		if actual, _ := payloadString(t.Payload, TokenTypeClaim); actual != typ {
+			return ErrWrongTokenType
+		}
+		return nil
+	}
+}
+
+// RefreshTokenStore tracks the latest refresh token id of every token family.
+type RefreshTokenStore interface {
+	CreateFamily(familyID, refreshID string, expiresAt time.Time) error
+	// Rotate replaces currentID of the family with nextID.
+	// If currentID is not the latest id of the family, the family is revoked and ErrRefreshTokenReused is returned.
+	Rotate(familyID, currentID, nextID string, expiresAt time.Time) error
+	RevokeFamily(familyID string) error
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// TokenPairIssuer issues access and refresh token pairs and rotates them on refresh.
+// Each refresh token belongs to a family (fid claim), presenting an already rotated refresh token revokes the whole family.
+type TokenPairIssuer struct {
+	createParser TokenCreateParser
+	store        RefreshTokenStore
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+}
+
+func NewTokenPairIssuer(createParser TokenCreateParser, store RefreshTokenStore, accessTTL, refreshTTL time.Duration) *TokenPairIssuer {
+	return &TokenPairIssuer{
+		createParser: createParser,
+		store:        store,
+		accessTTL:    accessTTL,
+		refreshTTL:   refreshTTL,
+	}
+}
+
+// IssueTokenPair starts a new token family, payload claims are copied to both tokens.
+func (i *TokenPairIssuer) IssueTokenPair(payload Payload) (TokenPair, error) {
+	familyID, err := NewTokenID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refreshID, err := NewTokenID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	err = i.store.CreateFamily(familyID, refreshID, now.Add(i.refreshTTL))
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("failed create token family, %w", err)
+	}
+	return i.createTokenPair(payload, familyID, refreshID, now)
+}
+
+// RefreshTokenPair verifies refresh token and rotates it together with the access token.
+func (i *TokenPairIssuer) RefreshTokenPair(refreshToken string) (TokenPair, error) {
+	token, err := i.createParser.ParseToken(refreshToken)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	err = ValidateToken(token, VerifyTokenExpiration, TokenTypeValidator(RefreshTokenType))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	familyID, ok := payloadString(token.Payload, "fid")
+	if !ok {
+		return TokenPair{}, ErrNoTokenFamily
+	}
+	refreshID, ok := payloadString(token.Payload, "jti")
+	if !ok {
+		return TokenPair{}, ErrNoTokenID
+	}
+
+	nextRefreshID, err := NewTokenID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+	now := time.Now()
+	err = i.store.Rotate(familyID, refreshID, nextRefreshID, now.Add(i.refreshTTL))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return i.createTokenPair(token.Payload, familyID, nextRefreshID, now)
+}
+
+func (i *TokenPairIssuer) RevokeFamily(familyID string) error {
+	return i.store.RevokeFamily(familyID)
+}
+
+func (i *TokenPairIssuer) createTokenPair(payload Payload, familyID, refreshID string, now time.Time) (TokenPair, error) {
+	accessID, err := NewTokenID()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	accessToken, err := i.createParser.CreateToken(
+		tokenPairPayload(payload, AccessTokenType, familyID, accessID, now, i.accessTTL),
+	)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("failed create access token, %w", err)
+	}
+	refreshToken, err := i.createParser.CreateToken(
+		tokenPairPayload(payload, RefreshTokenType, familyID, refreshID, now, i.refreshTTL),
+	)
+	if err != nil {
+		return TokenPair{}, fmt.Errorf("failed create refresh token, %w", err)
+	}
+	return TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func tokenPairPayload(payload Payload, typ, familyID, jti string, now time.Time, ttl time.Duration) Payload {
+	result := make(Payload, len(payload)+5)
+	for claim, value := range payload {
+		switch claim {
+		case TokenTypeClaim, "fid", "jti", "iat", "exp", "nbf":
+			continue
+		}
+		result[claim] = value
+	}
+	result[TokenTypeClaim] = typ
+	result["fid"] = familyID
+	result["jti"] = jti
+	result["iat"] = now.Unix()
+	result["exp"] = now.Add(ttl).Unix()
+	return result
+}
+
+type refreshTokenFamily struct {
+	currentID string
+	expiresAt time.Time
+	revoked   bool
+}
+
+// MemoryRefreshTokenStore is RefreshTokenStore that keeps families in memory until they expire.
+// Revoked families are kept until expiration too, so reuse of their tokens is still detected.
+type MemoryRefreshTokenStore struct {
+	mu        sync.Mutex
+	families  map[string]*refreshTokenFamily
+	lastSweep time.Time
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{
+		families:  make(map[string]*refreshTokenFamily),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryRefreshTokenStore) CreateFamily(familyID, refreshID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(time.Now())
+	s.families[familyID] = &refreshTokenFamily{currentID: refreshID, expiresAt: expiresAt}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) Rotate(familyID, currentID, nextID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+	family, ok := s.families[familyID]
+	if !ok || expired(family.expiresAt, now) {
+		return ErrTokenFamilyRevoked
+	}
+	if family.revoked {
+		return ErrTokenFamilyRevoked
+	}
+	if family.currentID != currentID {
+		family.revoked = true
+		return ErrRefreshTokenReused
+	}
+	family.currentID = nextID
+	family.expiresAt = expiresAt
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeFamily(familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if family, ok := s.families[familyID]; ok {
+		family.revoked = true
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for familyID, family := range s.families {
+		if expired(family.expiresAt, now) {
+			delete(s.families, familyID)
+		}
+	}
+}
@@ -7,8 +7,9 @@ import (
 //go:generate mockgen -source validate_token.go -destination mocks/validate_token_mocks.go -package jwtmocks
 
 var (
-	ErrNoExpiration TokenInvalidError = "invalid"
-	ErrTokenExpired TokenInvalidError = "expired"
+	ErrNoExpiration     TokenInvalidError = "invalid"
+	ErrTokenExpired     TokenInvalidError = "expired"
+	ErrTokenNotYetValid TokenInvalidError = "not_yet_valid"
 )
 
 type TokenValidator interface {
@@ -32,6 +33,15 @@ var VerifyTokenExpiration TokenValidatorFunc = func(t Token) error {
 	return nil
 }
 
+// VerifyTokenNotBefore rejects token before its nbf, token without nbf is valid.
+var VerifyTokenNotBefore TokenValidatorFunc = func(t Token) error {
+	nbf, ok := PayloadTime(t.Payload, "nbf")
+	if ok && time.Now().Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+	return nil
+}
+
 func ValidateToken(t Token, tokenValidators ...TokenValidator) error {
 	for _, tokenValidator := range tokenValidators {
 		err := tokenValidator.ValidateToken(t)